@@ -0,0 +1,76 @@
+package sentinels
+
+import "sort"
+
+// Heroes returns copies of the heroes in the data in use, sorted by name.
+// Hidden heroes are included, marked Hidden.
+func Heroes() []*Card {
+	return cardsOfType(Hero)
+}
+
+// Villains returns copies of the villains in the data in use, sorted by
+// name.
+func Villains() []*Card {
+	return cardsOfType(Villain)
+}
+
+// Environments returns copies of the environments in the data in use,
+// sorted by name.
+func Environments() []*Card {
+	return cardsOfType(Environment)
+}
+
+// AllCards returns copies of all the cards in the data in use, sorted by
+// name.
+func AllCards() []*Card {
+	return copyCards(func(*Card) bool { return true })
+}
+
+func cardsOfType(t CardType) []*Card {
+	return copyCards(func(c *Card) bool { return c.Type == t })
+}
+
+// copyCards returns copies of the cards for which keep is true, sorted by
+// name.
+func copyCards(keep func(*Card) bool) []*Card {
+	if EnsureData() != nil {
+		return nil
+	}
+	var cards []*Card
+	for _, c := range loaded().cards {
+		if keep(c) {
+			cc := *c
+			cards = append(cards, &cc)
+		}
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].Name < cards[j].Name })
+	return cards
+}
+
+// Scale returns a copy of the scale of the data in use, which maps
+// difficulty totals to loss percentages, highest total first.
+func Scale() []ScaleData {
+	return ScaleFor(0)
+}
+
+// ScaleFor returns a copy of the scale used for games with the given number
+// of heroes: the data's scale for that number if it has one, or else Scale.
+func ScaleFor(players int) []ScaleData {
+	if EnsureData() != nil {
+		return nil
+	}
+	return append([]ScaleData(nil), loaded().sd.scaleFor(players)...)
+}
+
+// HeroCountPoints returns the points for a game with the given number of
+// heroes, and whether the data has any.
+func HeroCountPoints(players int) (int, bool) {
+	if EnsureData() != nil {
+		return 0, false
+	}
+	sd := loaded().sd
+	if players < 3 || players > len(sd.Difficulty.Nump)+2 {
+		return 0, false
+	}
+	return sd.Difficulty.Nump[players-3].Points, true
+}
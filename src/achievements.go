@@ -0,0 +1,130 @@
+package sentinels
+
+import "time"
+
+// Achievement is a goal a group works toward over its games.
+type Achievement struct {
+	Name        string
+	Description string
+	Progress    int        // how much of the goal has been done
+	Goal        int        // how much must be done to earn it
+	Earned      *time.Time `json:",omitempty"` // when it was earned, if it has been
+}
+
+// Done reports whether the achievement has been earned.  One with nothing
+// to do, such as playing every hero of an empty collection, can't be.
+func (a *Achievement) Done() bool {
+	return a.Goal > 0 && a.Progress >= a.Goal
+}
+
+// tracker follows the progress toward an achievement through the played
+// games, counting the distinct keys the games it is given yield.
+type tracker struct {
+	a    *Achievement
+	keys func(e *HistoryEntry) []string
+	seen map[string]bool
+}
+
+// HardWinPercent is the expected loss percentage at which a win counts
+// toward the Against All Odds achievement.
+const HardWinPercent = 90
+
+// Achievements reports the group's progress toward each achievement, from
+// the games in the history with a recorded result.  Goals that cover a
+// collection, such as playing every hero, cover the cards in cs.
+func (h *History) Achievements(cs *CardSet) []*Achievement {
+	base := Select(WithExpansions(BaseSet))
+	won := func(e *HistoryEntry) bool { return e.Result == Won }
+	ts := []*tracker{
+		{a: &Achievement{Name: "First Victory", Description: "Win a game.", Goal: 1},
+			keys: func(e *HistoryEntry) []string {
+				if won(e) {
+					return []string{"won"}
+				}
+				return nil
+			}},
+		{a: &Achievement{Name: "Base Set Conqueror", Description: "Beat every villain in the base set.", Goal: len(bases(base.Villains))},
+			keys: func(e *HistoryEntry) []string {
+				if c, ok := Lookup(e.Villain); ok && won(e) && hasCard(base.Villains, c.Base) {
+					return []string{c.Base}
+				}
+				return nil
+			}},
+		{a: &Achievement{Name: "Against All Odds", Description: "Win a game with an expected loss of 90% or more.", Goal: 1},
+			keys: func(e *HistoryEntry) []string {
+				if won(e) && e.LossPercent >= HardWinPercent {
+					return []string{"won"}
+				}
+				return nil
+			}},
+		{a: &Achievement{Name: "Full Roster", Description: "Play every hero at least once.", Goal: len(bases(cs.Heroes))},
+			keys: func(e *HistoryEntry) []string {
+				var ks []string
+				for _, n := range e.Heroes {
+					if c, ok := Lookup(n); ok && hasCard(cs.Heroes, c.Base) {
+						ks = append(ks, c.Base)
+					}
+				}
+				return ks
+			}},
+		{a: &Achievement{Name: "World Tour", Description: "Play in every environment.", Goal: len(bases(cs.Environments))},
+			keys: func(e *HistoryEntry) []string {
+				if c, ok := Lookup(e.Environment); ok && hasCard(cs.Environments, c.Base) {
+					return []string{c.Base}
+				}
+				return nil
+			}},
+		{a: &Achievement{Name: "Veteran", Description: "Play 50 games.", Goal: 50},
+			keys: func(e *HistoryEntry) []string { return []string{e.Token} }},
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range h.Entries {
+		if e.Result == "" {
+			continue
+		}
+		for _, t := range ts {
+			if t.seen == nil {
+				t.seen = make(map[string]bool)
+			}
+			for _, k := range t.keys(e) {
+				if !t.seen[k] {
+					t.seen[k] = true
+					t.a.Progress++
+				}
+			}
+			if t.a.Done() && t.a.Earned == nil {
+				at := e.Time
+				t.a.Earned = &at
+			}
+		}
+	}
+	as := make([]*Achievement, len(ts))
+	for i, t := range ts {
+		if t.a.Progress > t.a.Goal {
+			t.a.Progress = t.a.Goal
+		}
+		as[i] = t.a
+	}
+	return as
+}
+
+// bases returns the names of the decks the cards are played with.
+func bases(cs []*Card) map[string]bool {
+	m := make(map[string]bool)
+	for _, c := range cs {
+		m[c.Base] = true
+	}
+	return m
+}
+
+// hasCard reports whether one of the cards is played with the named deck.
+func hasCard(cs []*Card, base string) bool {
+	for _, c := range cs {
+		if c.Base == base {
+			return true
+		}
+	}
+	return false
+}
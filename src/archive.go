@@ -0,0 +1,125 @@
+package sentinels
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Names of the files in an exported archive.
+const (
+	archiveProfiles = "profiles.json"
+	archiveHistory  = "history.json"
+	archiveCustom   = "custom.json"
+)
+
+// Export writes the profiles, presets and history to w as a zip archive,
+// for backing up or moving an installation, along with custom, the overlay
+// of custom cards, unless it is nil.  The card data and other overlays
+// aren't included.
+func Export(w io.Writer, ps *Profiles, h *History, custom []byte) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	z := zip.NewWriter(w)
+	for _, f := range []struct {
+		name string
+		v    interface{}
+	}{{archiveProfiles, ps}, {archiveHistory, h.Entries}} {
+		fw, err := z.Create(f.name)
+		if err != nil {
+			return err
+		}
+		b, err := json.MarshalIndent(f.v, "", "\t")
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(b); err != nil {
+			return err
+		}
+	}
+	if custom != nil {
+		fw, err := z.Create(archiveCustom)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(custom); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+// Import replaces the profiles, presets and history with those in an
+// archive written by Export, and saves them.  Nothing is replaced unless
+// the whole archive can be read.  If the archive holds custom cards,
+// restore is called with them first, to check them as LoadLayers does and
+// put them in place; if it fails, or is nil, nothing else is replaced.
+func Import(r io.ReaderAt, size int64, ps *Profiles, h *History, restore func(custom []byte) error) error {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	in := &Profiles{}
+	var entries []*HistoryEntry
+	var custom []byte
+	found := 0
+	for _, f := range z.File {
+		var v interface{}
+		switch f.Name {
+		case archiveProfiles:
+			v = in
+		case archiveHistory:
+			v = &entries
+		case archiveCustom:
+		default:
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		if v == nil {
+			custom = b
+			continue
+		}
+		if err := json.Unmarshal(b, v); err != nil {
+			return fmt.Errorf("Couldn't read %s from the archive: %v", f.Name, err)
+		}
+		found++
+	}
+	if found != 2 {
+		return errors.New("The archive is missing its profiles or history.")
+	}
+	if custom != nil {
+		if restore == nil {
+			return errors.New("The archive has custom cards, but there is nowhere to restore them.")
+		}
+		if err := restore(custom); err != nil {
+			return fmt.Errorf("Couldn't restore the custom cards: %v", err)
+		}
+	}
+
+	ps.mu.Lock()
+	ps.ByName, ps.Presets, ps.Players = in.ByName, in.Presets, in.Players
+	err = ps.save()
+	ps.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Entries = entries
+	h.index = nil
+	h.save()
+	return nil
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CalendarDays is how far ahead the calendar feed lists game nights.
+const CalendarDays = 60
+
+// DefaultSessionMinutes is how long a game night is taken to last when its
+// job doesn't say and there are no setups to go by.
+const DefaultSessionMinutes = 180
+
+// icsTime is the iCalendar form of a UTC time.
+const icsTime = "20060102T150405Z"
+
+// WriteCalendar writes the game nights of the jobs that have a Session in
+// the next CalendarDays as an iCalendar (RFC 5545) feed.  The first game
+// night after a job's latest run lists the setups it posted.
+func (sc *Scheduler) WriteCalendar(w io.Writer, now time.Time) error {
+	var b bytes.Buffer
+	line := func(s string) { b.WriteString(foldLine(s)) }
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//sentinels//scheduler//EN")
+	line("X-WR-CALNAME:Sentinels game nights")
+	stamp := now.UTC().Format(icsTime)
+	limit := now.AddDate(0, 0, CalendarDays)
+	for _, j := range sc.Config.Jobs {
+		if j.session == nil {
+			continue
+		}
+		sc.mu.Lock()
+		r := sc.runs[j.Name]
+		sc.mu.Unlock()
+		for t := j.session.Next(now); !t.IsZero() && t.Before(limit); t = j.session.Next(t) {
+			var desc string
+			from := j.session.prev(t)
+			if from.Before(now) {
+				from = now
+			}
+			if post := j.schedule.Next(from); !post.IsZero() && post.Before(t) {
+				desc = fmt.Sprintf("Setups will be posted %s.", post.Format("Monday 2 January at 15:04"))
+			}
+			minutes := j.Minutes
+			if r != nil && r.time.Before(t) && !r.time.Before(j.session.prev(t)) {
+				desc = sc.Config.message(j, r.setups)
+				if minutes == 0 {
+					for _, s := range r.setups {
+						minutes += int(s.Duration() / time.Minute)
+					}
+				}
+			}
+			if minutes == 0 {
+				minutes = DefaultSessionMinutes
+			}
+			line("BEGIN:VEVENT")
+			line(fmt.Sprintf("UID:%d-%s@sentinels", t.Unix(), strings.Replace(icsText(j.Name), " ", "-", -1)))
+			line("DTSTAMP:" + stamp)
+			line("DTSTART:" + t.UTC().Format(icsTime))
+			line("DTEND:" + t.Add(time.Duration(minutes)*time.Minute).UTC().Format(icsTime))
+			line("SUMMARY:" + icsText(j.Name))
+			if desc != "" {
+				line("DESCRIPTION:" + icsText(desc))
+			}
+			line("END:VEVENT")
+		}
+	}
+	line("END:VCALENDAR")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// prev returns the time in the schedule before t, looking back as far as
+// the calendar does, or the zero time if there is none.
+func (s *Schedule) prev(t time.Time) time.Time {
+	var last time.Time
+	for p := s.Next(t.AddDate(0, 0, -CalendarDays)); !p.IsZero() && p.Before(t); p = s.Next(p) {
+		last = p
+	}
+	return last
+}
+
+// icsText escapes text for an iCalendar property value.
+func icsText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// foldLine ends an iCalendar content line, folding it so that no line is
+// longer than 75 bytes, without splitting a UTF-8 sequence.
+func foldLine(s string) string {
+	var b bytes.Buffer
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > 75 {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a cron-style schedule: minute, hour, day of month, month and
+// day of week, each "*", a number, a range such as "1-5", a step such as
+// "*/15", or a comma-separated list of those.  Sunday is day 0 (or 7).  As
+// in cron, if both days are restricted a time matches either.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	anyDom, anyDow                bool
+}
+
+// ParseSchedule parses a five-field cron schedule.
+func ParseSchedule(spec string) (*Schedule, error) {
+	f := strings.Fields(spec)
+	if len(f) != 5 {
+		return nil, fmt.Errorf("A schedule has 5 fields, not %d: %q.", len(f), spec)
+	}
+	s := &Schedule{anyDom: f[2] == "*", anyDow: f[4] == "*"}
+	for _, p := range []struct {
+		field    string
+		bits     *uint64
+		min, max int
+	}{
+		{f[0], &s.minute, 0, 59},
+		{f[1], &s.hour, 0, 23},
+		{f[2], &s.dom, 1, 31},
+		{f[3], &s.month, 1, 12},
+		{f[4], &s.dow, 0, 7},
+	} {
+		b, err := parseField(p.field, p.min, p.max)
+		if err != nil {
+			return nil, fmt.Errorf("Bad schedule %q: %v", spec, err)
+		}
+		*p.bits = b
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseField returns the values a field selects as a bit set.
+func parseField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("bad step in %q", part)
+			}
+			part, step = part[:i], n
+		}
+		lo, hi := min, max
+		if part != "*" {
+			r := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(r[0]); err != nil {
+				return 0, fmt.Errorf("bad value %q", part)
+			}
+			hi = lo
+			if len(r) == 2 {
+				if hi, err = strconv.Atoi(r[1]); err != nil {
+					return 0, fmt.Errorf("bad range %q", part)
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// has reports whether bit v is set.
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
+
+// dayMatches reports whether t's day is in the schedule.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom, dow := has(s.dom, t.Day()), has(s.dow, int(t.Weekday()))
+	switch {
+	case s.anyDom && s.anyDow:
+		return true
+	case s.anyDom:
+		return dow
+	case s.anyDow:
+		return dom
+	}
+	return dom || dow
+}
+
+// Next returns the first time in the schedule after t, to the minute, or
+// the zero time if there is none in the next five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !has(s.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(s.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(s.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"fmt"
+	"net/smtp"
+	"net/url"
+	"strings"
+
+	"github.com/uhhhclem/sentinels"
+	"github.com/uhhhclem/sentinels/bots/webhook"
+)
+
+// Mail is how setups are sent to mailto: addresses.
+type Mail struct {
+	Server   string // host:port of the SMTP server
+	From     string
+	User     string `json:",omitempty"`
+	Password string `json:",omitempty"`
+}
+
+// post sends a message, and the setups it is about, where the job says.
+func (c *Config) post(j *Job, text string, setups []*sentinels.Setup) error {
+	u, err := url.Parse(j.Post)
+	if err != nil {
+		return err
+	}
+	switch {
+	case u.Scheme == "mailto":
+		return c.Mail.send(u.Opaque, "Sentinels: "+j.Name, text)
+	case strings.HasSuffix(u.Host, "discord.com") || strings.HasSuffix(u.Host, "discordapp.com"):
+		return webhook.Post(j.Post, map[string]string{"content": text})
+	case u.Host == "hooks.slack.com":
+		return webhook.Post(j.Post, map[string]string{"text": text})
+	}
+	return webhook.Post(j.Post, struct {
+		Job    string
+		Text   string
+		Setups []*sentinels.Setup
+	}{j.Name, text, setups})
+}
+
+// send emails text to a comma-separated list of addresses.
+func (m *Mail) send(to, subject, text string) error {
+	if m.Server == "" || m.From == "" {
+		return fmt.Errorf("No mail server is configured to send to %s.", to)
+	}
+	var auth smtp.Auth
+	if m.User != "" {
+		auth = smtp.PlainAuth("", m.User, m.Password, strings.Split(m.Server, ":")[0])
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s",
+		m.From, to, subject, strings.Replace(text, "\n", "\r\n", -1))
+	return smtp.SendMail(m.Server, auth, m.From, strings.Split(to, ","), []byte(msg))
+}
@@ -0,0 +1,242 @@
+// Package scheduler pre-generates setups on a cron-style schedule, e.g.
+// the afternoon before game night, and posts them to a Discord or Slack
+// webhook or by email.  A job can instead send the group's organizer a
+// weekly digest of its games.  Game nights, with the setups posted for
+// them, can be listed in a calendar feed; see WriteCalendar.
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// Job is a scheduled batch of setups.
+type Job struct {
+	Name     string
+	Schedule string // when to post; see Schedule
+	// Profile and Preset name a collection profile and a preset of search
+	// parameters in the profiles; Players and LossPercent, if set,
+	// override the preset's.
+	Profile     string `json:",omitempty"`
+	Preset      string `json:",omitempty"`
+	Players     int    `json:",omitempty"`
+	LossPercent int    `json:",omitempty"`
+	Games       int    `json:",omitempty"` // setups to post; default 1
+	// Digest posts a digest of the past DigestDays' games, with challenges
+	// suggested from the profile or preset, instead of setups to play.
+	Digest bool `json:",omitempty"`
+	// Post is where the setups go: a Discord or Slack webhook URL, a
+	// mailto: URL, or any other URL, which is sent the setups as JSON.
+	Post string
+	// Session, if set, is when the game night the setups are for starts,
+	// as a schedule; the game nights are listed in the calendar feed.
+	// Minutes is how long one lasts; the default is the time the setups
+	// take to play, or DefaultSessionMinutes before there are any.
+	Session string `json:",omitempty"`
+	Minutes int    `json:",omitempty"`
+
+	schedule *Schedule
+	session  *Schedule
+}
+
+// Config is a set of jobs and what they need to run.
+type Config struct {
+	Jobs []*Job
+	// BaseURL, if set, is the web app's address, for links to the setups.
+	BaseURL string `json:",omitempty"`
+	Mail    Mail
+}
+
+// LoadConfig reads a JSON scheduler configuration and checks its schedules.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+	for _, j := range c.Jobs {
+		if j.schedule, err = ParseSchedule(j.Schedule); err != nil {
+			return nil, fmt.Errorf("Job %q: %v", j.Name, err)
+		}
+		if j.Session != "" {
+			if j.session, err = ParseSchedule(j.Session); err != nil {
+				return nil, fmt.Errorf("Job %q session: %v", j.Name, err)
+			}
+		}
+	}
+	return c, nil
+}
+
+// Find returns the job with the given name, or nil if there is none.
+func (c *Config) Find(name string) *Job {
+	for _, j := range c.Jobs {
+		if j.Name == name {
+			return j
+		}
+	}
+	return nil
+}
+
+// Scheduler runs the jobs of a configuration.
+type Scheduler struct {
+	Config   *Config
+	Profiles *sentinels.Profiles
+
+	mu   sync.Mutex
+	stop chan struct{}
+	runs map[string]*run // each job's latest run, by name
+}
+
+// run is a job's run: when it was and the setups it posted.
+type run struct {
+	time   time.Time
+	setups []*sentinels.Setup
+}
+
+// New returns a scheduler for the jobs in c, finding setups with the
+// profiles and presets in ps.
+func New(c *Config, ps *sentinels.Profiles) *Scheduler {
+	return &Scheduler{Config: c, Profiles: ps}
+}
+
+// Start runs each job at its scheduled times until Stop is called.
+func (sc *Scheduler) Start() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.stop != nil {
+		return
+	}
+	sc.stop = make(chan struct{})
+	for _, j := range sc.Config.Jobs {
+		go sc.loop(j, sc.stop)
+	}
+}
+
+// Stop stops the scheduler.
+func (sc *Scheduler) Stop() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.stop != nil {
+		close(sc.stop)
+		sc.stop = nil
+	}
+}
+
+func (sc *Scheduler) loop(j *Job, stop chan struct{}) {
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("Job %q will never run again.", j.Name)
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(time.Until(next)):
+			if err := sc.Run(j); err != nil {
+				log.Printf("Job %q: %v", j.Name, err)
+			}
+		}
+	}
+}
+
+// Run generates a job's setups, or its digest, and posts them now.
+func (sc *Scheduler) Run(j *Job) error {
+	if j.Digest {
+		return sc.digest(j)
+	}
+	setups, err := sc.generate(j)
+	if err != nil {
+		return err
+	}
+	sc.mu.Lock()
+	if sc.runs == nil {
+		sc.runs = make(map[string]*run)
+	}
+	sc.runs[j.Name] = &run{time.Now(), setups}
+	sc.mu.Unlock()
+	return sc.Config.post(j, sc.Config.message(j, setups), setups)
+}
+
+// digest posts a digest of the group's recent games.
+func (sc *Scheduler) digest(j *Job) error {
+	p, err := sc.params(j)
+	if err != nil {
+		return err
+	}
+	d, err := sentinels.DefaultHistory.Digest(time.Now().AddDate(0, 0, -sentinels.DigestDays), p)
+	if err != nil {
+		return err
+	}
+	return sc.Config.post(j, d.String(), d.Challenges)
+}
+
+// generate finds a job's setups.  They are recorded in the history like
+// any other, so they can be vetoed, replayed and scored.
+func (sc *Scheduler) generate(j *Job) ([]*sentinels.Setup, error) {
+	n := j.Games
+	if n < 1 {
+		n = 1
+	}
+	var setups []*sentinels.Setup
+	for i := 0; i < n; i++ {
+		// each game's villain comes out of the profile's rotation, if any.
+		p, err := sc.params(j)
+		if err != nil {
+			return nil, err
+		}
+		s, _, err := sentinels.Find(p)
+		if err != nil {
+			return nil, err
+		}
+		setups = append(setups, s)
+	}
+	return setups, nil
+}
+
+// params returns the search parameters of a job.
+func (sc *Scheduler) params(j *Job) (*sentinels.Params, error) {
+	p := &sentinels.Params{Players: 3, LossPercent: 50, Range: 10, Expansions: []sentinels.ExpansionType{sentinels.BaseSet}}
+	if j.Preset != "" {
+		if p = sc.Profiles.Preset(j.Preset); p == nil {
+			return nil, fmt.Errorf("No preset named %q.", j.Preset)
+		}
+	}
+	if j.Profile != "" {
+		pr := sc.Profiles.Get(j.Profile)
+		if pr == nil {
+			return nil, fmt.Errorf("No profile named %q.", j.Profile)
+		}
+		pr.Apply(p)
+	}
+	if j.Players != 0 {
+		p.Players = j.Players
+	}
+	if j.LossPercent != 0 {
+		p.LossPercent, p.ByTotal = j.LossPercent, false
+	}
+	return p, nil
+}
+
+// message formats setups for posting.
+func (c *Config) message(j *Job, setups []*sentinels.Setup) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Setups for %s:\n", j.Name)
+	for i, s := range setups {
+		fmt.Fprintf(&b, "\n%d. %s\n%s\n", i+1, s, s.Describe())
+		if c.BaseURL != "" {
+			fmt.Fprintf(&b, "%s/score?%s\n", c.BaseURL, s.ShareQuery())
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+// Package webhook posts setups as JSON to configured URLs as they are
+// generated and played, for home automation, stream overlays, logging and
+// the like.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// Payload is the JSON body of a webhook post.
+type Payload struct {
+	Event string // sentinels.Generated or sentinels.Played
+	Time  time.Time
+	Setup *sentinels.Setup `json:",omitempty"`
+	Entry *sentinels.HistoryEntry
+}
+
+// Hooks posts events to a set of URLs.
+type Hooks struct {
+	URLs []string
+	// Events limits the posts to these kinds of event; empty means all.
+	Events []string
+
+	wg sync.WaitGroup
+}
+
+// Listen starts posting the package's events to the hooks' URLs.  Posts are
+// made in the background and failures logged.
+func (h *Hooks) Listen() {
+	sentinels.Listen(h.fire)
+}
+
+// Wait waits for the posts under way to finish.
+func (h *Hooks) Wait() {
+	h.wg.Wait()
+}
+
+func (h *Hooks) fire(e sentinels.Event) {
+	if len(h.Events) > 0 && !contains(h.Events, e.Kind) {
+		return
+	}
+	b, err := json.Marshal(&Payload{e.Kind, sentinels.Now(), e.Setup, e.Entry})
+	if err != nil {
+		log.Printf("Couldn't encode webhook payload: %v", err)
+		return
+	}
+	for _, u := range h.URLs {
+		h.wg.Add(1)
+		go func(u string) {
+			defer h.wg.Done()
+			if err := post(u, b); err != nil {
+				log.Println(err)
+			}
+		}(u)
+	}
+}
+
+// Post posts v as JSON to a URL.
+func Post(url string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return post(url, b)
+}
+
+// client is used for posts, so that a slow endpoint can't hold them up
+// for long.
+var client = &http.Client{Timeout: 10 * time.Second}
+
+func post(url string, b []byte) error {
+	resp, err := client.Post(url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Posting to %s failed: %s", url, resp.Status)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package sentinels
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Bundle is a group of expansions sold together, named as the digital
+// edition's DLC is, so that it can be selected in one go.
+type Bundle struct {
+	Name       string // the short name ParseExpansions accepts
+	Title      string // the store's name for it
+	Expansions []ExpansionType
+}
+
+// Bundles are the digital edition's bundles of DLC.  Their names, titles
+// and aliases are all accepted by ParseExpansions.
+var Bundles = []Bundle{
+	{"seasonpass1", "Season Pass 1", []ExpansionType{MiniExpansion, RookCity, InfernalRelics, ShatteredTimelines}},
+	{"seasonpass2", "Season Pass 2", []ExpansionType{Vengeance}},
+	{"complete", "Complete Edition", []ExpansionType{BaseSet, MiniExpansion, RookCity, InfernalRelics, ShatteredTimelines, Vengeance, Promos}},
+}
+
+// ExpansionAliases maps other names for expansions and bundles, such as the
+// digital edition's names for its DLC, to their short names.  Keys are in
+// the form expansionKey gives them.
+var ExpansionAliases = map[string]string{
+	"base":          "baseset",
+	"basegame":      "baseset",
+	"core":          "baseset",
+	"minipacks":     "miniexpansion",
+	"minipack":      "miniexpansion",
+	"minis":         "miniexpansion",
+	"sp1":           "seasonpass1",
+	"sp2":           "seasonpass2",
+	"seasonpassone": "seasonpass1",
+	"seasonpasstwo": "seasonpass2",
+	"definitive":    "complete",
+	"all":           "complete",
+}
+
+// expansionKey reduces a name to the form names are matched in: lower
+// case, without spaces or punctuation, so that "Season Pass 1" and
+// "season-pass-1" are the same.
+func expansionKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// lookupExpansions returns the expansions a name selects: one for an
+// expansion's short name or title, or several for a bundle's, following
+// ExpansionAliases.
+func lookupExpansions(name string) ([]ExpansionType, error) {
+	k := expansionKey(name)
+	if a, ok := ExpansionAliases[k]; ok {
+		k = a
+	}
+	for i := range ExpansionNames {
+		e := ExpansionType(i)
+		if k == ExpansionNames[i] || k == expansionKey(e.Title()) {
+			return []ExpansionType{e}, nil
+		}
+	}
+	for _, b := range Bundles {
+		if k == b.Name || k == expansionKey(b.Title) {
+			return append([]ExpansionType(nil), b.Expansions...), nil
+		}
+	}
+	return nil, fmt.Errorf("Unknown expansion %q.", name)
+}
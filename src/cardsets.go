@@ -0,0 +1,66 @@
+package sentinels
+
+import "sort"
+
+// Union returns a card set holding every card in cs or any of the others,
+// e.g. two friends' collections played at one table.  Each card appears
+// once, and the cards are sorted by name as in GetCardSet.
+func (cs *CardSet) Union(others ...*CardSet) *CardSet {
+	u := &CardSet{}
+	seen := make(map[string]bool)
+	for _, s := range append([]*CardSet{cs}, others...) {
+		for _, c := range s.all() {
+			if !seen[c.Name] {
+				seen[c.Name] = true
+				u.add(c)
+			}
+		}
+	}
+	for _, l := range [][]*Card{u.Heroes, u.Villains, u.Environments} {
+		sort.Sort(byName(l))
+	}
+	return u
+}
+
+// Intersect returns a copy of the card set holding only the cards also in
+// other.
+func (cs *CardSet) Intersect(other *CardSet) *CardSet {
+	return cs.filter(other.Contains)
+}
+
+// Subtract returns a copy of the card set omitting the cards in other, e.g.
+// the decks lent out of a collection.
+func (cs *CardSet) Subtract(other *CardSet) *CardSet {
+	return cs.filter(func(c *Card) bool { return !other.Contains(c) })
+}
+
+// Contains reports whether the card set holds a card with c's name.
+func (cs *CardSet) Contains(c *Card) bool {
+	for _, x := range cs.all() {
+		if x.Name == c.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// all returns the card set's heroes, villains and environments.
+func (cs *CardSet) all() []*Card {
+	var all []*Card
+	for _, l := range [][]*Card{cs.Heroes, cs.Villains, cs.Environments} {
+		all = append(all, l...)
+	}
+	return all
+}
+
+// add adds c to the list for its type.
+func (cs *CardSet) add(c *Card) {
+	switch c.Type {
+	case Hero:
+		cs.Heroes = append(cs.Heroes, c)
+	case Villain:
+		cs.Villains = append(cs.Villains, c)
+	case Environment:
+		cs.Environments = append(cs.Environments, c)
+	}
+}
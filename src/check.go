@@ -0,0 +1,166 @@
+package sentinels
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Check verifies that s is a setup p could have produced: it has p.Players
+// heroes including every hero p requires, no more heroes share a base than
+// there are copies of its deck, every other card is in the card set p
+// selects, and unless s is Approximate its difficulty is in the range p
+// targets.
+func (s *Setup) Check(p *Params) error {
+	if len(s.Heroes) != p.Players {
+		return fmt.Errorf("The setup has %d heroes, not %d.", len(s.Heroes), p.Players)
+	}
+	in := make(map[*Card]bool)
+	cs := p.CardSet()
+	for _, l := range [][]*Card{cs.Heroes, cs.Villains, cs.Environments} {
+		for _, c := range l {
+			in[c] = true
+		}
+	}
+	required := make(map[string]int)
+	for _, n := range p.Heroes {
+		if c, ok := Lookup(n); ok {
+			n = c.Name
+		}
+		required[n]++
+	}
+	bases := make(map[string]int)
+	for _, h := range s.Heroes {
+		if h.Type != Hero {
+			return fmt.Errorf("%s is not a hero.", h.Name)
+		}
+		if bases[h.Base]++; bases[h.Base] > p.copies(h) {
+			return fmt.Errorf("More versions of %s are in the setup than there are decks for.", h.Base)
+		}
+		if !in[h] && required[h.Name] == 0 {
+			return fmt.Errorf("%s is not in the selected card set.", h.Name)
+		}
+		required[h.Name]--
+	}
+	for n, k := range required {
+		if k > 0 {
+			return fmt.Errorf("Required hero %s is missing.", n)
+		}
+	}
+	if s.Villain.Type != Villain || !in[s.Villain] {
+		return fmt.Errorf("%s is not a villain in the selected card set.", s.Villain.Name)
+	}
+	if s.Environment.Type != Environment || !in[s.Environment] {
+		return fmt.Errorf("%s is not an environment in the selected card set.", s.Environment.Name)
+	}
+	if s.Difficulty != Model.Score(s) {
+		return fmt.Errorf("The setup's difficulty is %d but it scores %d.", s.Difficulty, Model.Score(s))
+	}
+	if s.Approximate {
+		return nil
+	}
+	min, max := p.TargetTotal-p.Tolerance, p.TargetTotal+p.Tolerance
+	if !p.ByTotal {
+		ds := loaded()
+		if s.search != nil {
+			ds = s.search.data
+		}
+		_, min, max = p.lossRange(ds.sd)
+	}
+	if s.Difficulty < min || s.Difficulty > max {
+		return fmt.Errorf("The setup's difficulty %d is outside %d to %d.", s.Difficulty, min, max)
+	}
+	return nil
+}
+
+// CheckFailure is a set of parameters for which generation broke an
+// invariant or panicked.
+type CheckFailure struct {
+	Params *Params
+	Err    error
+}
+
+func (f *CheckFailure) Error() string {
+	return fmt.Sprintf("%v (params: %+v)", f.Err, *f.Params)
+}
+
+// CheckGeneration runs n searches with random parameters drawn from seed and
+// checks each setup found with Check.  Searches may fail, e.g. when the
+// parameters ask for more heroes than the card set has; a search that
+// panics or returns a setup that fails Check is reported as a
+// *CheckFailure.  Nothing is recorded in the history.  It returns the
+// number of searches that found a setup.
+func CheckGeneration(n int, seed int64) (int, error) {
+	if err := EnsureData(); err != nil {
+		return 0, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+	found := 0
+	for i := 0; i < n; i++ {
+		p := randomParams(rng)
+		s, err := checkOne(p, rng.Int63())
+		if f, ok := err.(*CheckFailure); ok {
+			return found, f
+		}
+		if s != nil {
+			found++
+		}
+	}
+	return found, nil
+}
+
+// checkOne runs one search and checks its result, turning panics and
+// broken invariants into a *CheckFailure.
+func checkOne(p *Params, seed int64) (s *Setup, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s, err = nil, &CheckFailure{p, fmt.Errorf("panic: %v", r)}
+		}
+	}()
+	q, err := newSearchFor(p)
+	if err != nil {
+		return nil, err
+	}
+	s, _, err = q.run(seed)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Check(p); err != nil {
+		return nil, &CheckFailure{p, err}
+	}
+	return s, nil
+}
+
+// randomParams makes search parameters for CheckGeneration, now and then
+// out of range to exercise the error paths.
+func randomParams(rng *rand.Rand) *Params {
+	p := &Params{
+		Players:       2 + rng.Intn(5),
+		LossPercent:   rng.Intn(101),
+		Range:         rng.Intn(11),
+		Advanced:      rng.Intn(4) == 0,
+		MaxIterations: 2000,
+	}
+	if rng.Intn(4) == 0 {
+		p.ByTotal = true
+		p.TargetTotal = rng.Intn(301) - 50
+		p.Tolerance = rng.Intn(11)
+	}
+	for e := range ExpansionNames {
+		if rng.Intn(2) == 0 {
+			p.Expansions = append(p.Expansions, ExpansionType(e))
+		}
+	}
+	if rng.Intn(5) == 0 {
+		p.Tier = Tier(1 + rng.Intn(4))
+	}
+	if rng.Intn(5) == 0 {
+		p.Copies = map[ExpansionType]int{BaseSet: 2}
+	}
+	if rng.Intn(3) == 0 {
+		heroes := Select(WithExpansions(allExpansions()...)).Heroes
+		for k := rng.Intn(3); k > 0; k-- {
+			p.Heroes = append(p.Heroes, heroes[rng.Intn(len(heroes))].Name)
+		}
+	}
+	return p
+}
@@ -0,0 +1,80 @@
+package sentinels
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// DeterministicEpoch is the time Now starts at in deterministic mode.
+var DeterministicEpoch = time.Date(2014, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+var (
+	clockMu sync.Mutex
+	rng     *rand.Rand // nil means math/rand/v2's own generator
+	clock   time.Time  // the next time Now returns; zero means the real time
+)
+
+// Deterministic makes the package reproducible, e.g. for end-to-end tests
+// and demo recordings: the random numbers it chooses seeds and tokens with
+// come from seed, and Now returns start, then a second later on each call.
+// Call it before generating any setups.
+func Deterministic(seed int64, start time.Time) {
+	clockMu.Lock()
+	defer clockMu.Unlock()
+	rng = rand.New(rand.NewPCG(uint64(seed), pcgStream))
+	clock = start
+}
+
+// Now returns the time recorded for setups, results and other events.  It
+// is the real time unless Deterministic was called.
+func Now() time.Time {
+	clockMu.Lock()
+	defer clockMu.Unlock()
+	if clock.IsZero() {
+		return time.Now()
+	}
+	t := clock
+	clock = clock.Add(time.Second)
+	return t
+}
+
+// NewSeed returns a random seed.  In deterministic mode the seeds follow
+// from the one given to Deterministic.
+func NewSeed() int64 {
+	if r := lockedRand(); r != nil {
+		defer clockMu.Unlock()
+		return r.Int64()
+	}
+	return rand.Int64()
+}
+
+// randIntn and randPerm are rand.IntN and rand.Perm using the package's
+// generator.
+func randIntn(n int) int {
+	if r := lockedRand(); r != nil {
+		defer clockMu.Unlock()
+		return r.IntN(n)
+	}
+	return rand.IntN(n)
+}
+
+func randPerm(n int) []int {
+	if r := lockedRand(); r != nil {
+		defer clockMu.Unlock()
+		return r.Perm(n)
+	}
+	return rand.Perm(n)
+}
+
+// lockedRand returns the deterministic generator with clockMu held, or nil,
+// without it held, if the package isn't deterministic.  The generator
+// math/rand/v2 uses otherwise needs no lock.
+func lockedRand() *rand.Rand {
+	clockMu.Lock()
+	if rng == nil {
+		clockMu.Unlock()
+		return nil
+	}
+	return rng
+}
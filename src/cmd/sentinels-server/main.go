@@ -0,0 +1,66 @@
+// Command sentinels-server serves the sentinels web app standalone, e.g.
+// from a container.  It is configured from a configuration file, if there
+// is one, and then from the environment (see httpserver.ConfigFromEnv);
+// the templates and static files are built in.  It shuts down cleanly on
+// SIGTERM or SIGINT, letting requests in progress finish.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/uhhhclem/sentinels/httpserver"
+)
+
+// shutdownGrace is how long requests in progress get to finish.
+const shutdownGrace = 10 * time.Second
+
+var (
+	configPath = flag.String("config", "", "configuration file; see package config")
+	addr       = flag.String("addr", "", "listen address, overriding the configuration")
+	seed       = flag.Int64("seed", 0, "if nonzero, fix random choices and timestamps for reproducible tests and demos")
+)
+
+func main() {
+	flag.Parse()
+	c, err := httpserver.ConfigFromFile(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *addr != "" {
+		c.Addr = *addr
+	}
+	if *seed != 0 {
+		c.Seed = *seed
+	}
+	h, err := httpserver.NewHandler(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv := &http.Server{Addr: c.Addr, Handler: h}
+
+	done := make(chan struct{})
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGTERM, os.Interrupt)
+		log.Printf("Shutting down on %v", <-sig)
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Print(err)
+		}
+		close(done)
+	}()
+
+	log.Printf("Listening on %s", c.Addr)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	<-done
+}
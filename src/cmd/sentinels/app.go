@@ -0,0 +1,603 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/uhhhclem/sentinels"
+	"github.com/uhhhclem/sentinels/qr"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	pc    int
+	lp    int
+	rg    int
+	tt    int
+	tol   int
+	promo bool
+	lang  string
+	plain bool
+	hist  string
+	veto  bool
+	fam   bool
+	prof  string
+	profs string
+	exps  string
+	hexps string
+	vexps string
+	eexps string
+	cops  string
+	excl  listFlag
+	proms listFlag
+	pre   string
+	save  string
+	names string
+	draft int
+	pools listFlag
+	avoid listFlag
+	delta int
+	adv   bool
+	advm  bool
+	conf  bool
+	thru  string
+	seed  int64
+	rplay string
+	maxit int
+	maxt  time.Duration
+	pc2   int
+	check bool
+	cover bool
+	xvar  bool
+	tier  string
+	nem   string
+	style string
+	syn   string
+	minsy int
+	acon  bool
+	fitd  bool
+	level string
+	plan  time.Duration
+	plps  string
+	verb  bool
+	fair  bool
+	teach bool
+	table bool
+	box   bool
+	quiet bool
+	qrURL string
+	qrPNG string
+	qrt   bool
+	hooks listFlag
+)
+
+// generate finds a setup; it's the default command.
+func generate(args []string) {
+	flag.IntVar(&pc, "pc", cfg.Players, "player count (3-5)")
+	flag.IntVar(&lp, "lp", cfg.LossPercent, "target loss percent (1-99)")
+	flag.IntVar(&rg, "rg", 10, "allowable difficulty variance around target loss percent (0-100, default 10")
+	flag.StringVar(&level, "level", "", "named difficulty in place of -lp: "+strings.Join(sentinels.LevelNames(), ", "))
+	flag.IntVar(&tt, "tt", 0, "target difficulty total (overrides -lp and -rg when set)")
+	flag.IntVar(&tol, "tol", 10, "allowable difficulty variance around target total (0-100, default 10)")
+	flag.StringVar(&lang, "lang", "", "language for card names (e.g. es, de)")
+	flag.BoolVar(&table, "table", false, "print the setup as an aligned table")
+	flag.BoolVar(&box, "box", false, "with -table, draw the table with box-drawing characters")
+	flag.BoolVar(&plain, "plain", false, "print the setup as screen-reader-friendly plain text")
+	flag.StringVar(&hist, "hist", cfg.Storage.History, "file in which to keep the history of generated setups")
+	flag.BoolVar(&veto, "veto", false, "offer to veto and regenerate the setup")
+	flag.BoolVar(&fam, "family", false, "family mode: leave out dark content")
+	flag.StringVar(&prof, "profile", "", "name of the profile to use")
+	flag.StringVar(&profs, "profiles", cfg.Storage.Profiles, "file containing saved profiles and presets")
+	flag.StringVar(&exps, "exp", strings.Join(cfg.Expansions, ","), "comma-separated expansions or bundles, e.g. \"Season Pass 1\", to use")
+	flag.StringVar(&hexps, "heroexp", "", "comma-separated expansions to take heroes from, in place of -exp")
+	flag.StringVar(&vexps, "villainexp", "", "comma-separated expansions to take villains from, in place of -exp")
+	flag.StringVar(&cops, "copies", "", "expansions owned more than once, as expansion=count pairs, e.g. baseset=2")
+	flag.StringVar(&eexps, "envexp", "", "comma-separated expansions to take environments from, in place of -exp")
+	flag.BoolVar(&xvar, "excludevariants", false, "also exclude cards sharing a deck with an excluded card")
+	flag.StringVar(&thru, "through", "", "use every expansion released up to and including this one (overrides -exp)")
+	flag.Var(&excl, "exclude", "name of a card not to use (may be repeated)")
+	flag.Var(&proms, "promo", "name of a promo card owned, if not all of them (may be repeated)")
+	flag.StringVar(&pre, "preset", "", "name of a saved preset to start from; other flags override it")
+	flag.StringVar(&save, "save", "", "save the parameters as a preset with this name")
+	flag.StringVar(&names, "players", "", "comma-separated player names to seat at the table, honoring their registered preferences")
+	flag.BoolVar(&fair, "fair", false, "rotate hero archetypes among the -players across games in the -hist file")
+	flag.BoolVar(&teach, "teach", false, "set up a teaching game for new players: simple heroes and villain, a calm environment and a forgiving target")
+	flag.IntVar(&draft, "draft", 0, "deal each player this many heroes to choose from")
+	flag.Var(&pools, "pool", "environment pool to choose from (may be repeated): "+strings.Join(sentinels.EnvironmentPools(), ", "))
+	flag.Var(&avoid, "avoidpool", "environment pool not to use (may be repeated)")
+	flag.BoolVar(&adv, "adv", false, "play the villain in advanced mode")
+	flag.BoolVar(&advm, "advmissing", false, "in advanced mode, allow villains with no advanced-mode data")
+	flag.StringVar(&tier, "tier", "", "villain tier to use: easy, medium, hard or brutal")
+	flag.BoolVar(&acon, "allowconflicts", false, "allow villain and environment pairings the data lists as conflicting")
+	flag.StringVar(&nem, "nemesis", "", "prefer or avoid setups pitting a hero against their nemesis villain")
+	flag.StringVar(&style, "style", "", "how the target is reached: powerfantasy (strong heroes, hard villain) or underdog (weak heroes, easy villain)")
+	flag.BoolVar(&conf, "confident", false, "in advanced mode, only use villains with plenty of recorded games")
+	flag.Int64Var(&seed, "seed", 0, "seed for the random number generator (0 for a random seed)")
+	flag.StringVar(&rplay, "replay", "", "token of a setup in the -hist file to regenerate exactly")
+	flag.IntVar(&maxit, "maxiter", sentinels.DefaultMaxIterations, "number of setups to try before settling for the closest")
+	flag.DurationVar(&maxt, "maxtime", 0, "time to search before settling for the closest setup (e.g. 2s; 0 for no limit)")
+	flag.IntVar(&pc2, "table2", 0, "player count at a second table sharing the collection (3-5)")
+	flag.BoolVar(&check, "checklist", false, "list the boxes and components to fetch")
+	flag.StringVar(&syn, "synergy", "", "JSON file of hero synergy rules to rate teams by")
+	flag.IntVar(&minsy, "minsynergy", 0, "lowest team synergy to accept with -synergy")
+	flag.BoolVar(&fitd, "fit", false, "score setups with card points fitted to the results in the -hist file")
+	flag.BoolVar(&cover, "coverage", false, "report cards never played in the -hist file and suggest setups that use them")
+	flag.DurationVar(&plan, "plan", 0, "plan as many games as fit in this much time (e.g. 3h)")
+	flag.StringVar(&plps, "planlp", "", "comma-separated loss percents for the planned games, taken in turn (default -lp)")
+	flag.BoolVar(&verb, "v", false, "verbose: also print search statistics, the difficulty breakdown and the data version")
+	flag.BoolVar(&quiet, "q", false, "quiet: print only the setup")
+	flag.Var(&hooks, "webhook", "URL to post the setup to as JSON (may be repeated)")
+	flag.BoolVar(&qrt, "qr", false, "print a QR code of the setup")
+	flag.StringVar(&qrURL, "qrurl", "", "with -qr or -qrpng, encode a link to the setup on the web app at this URL instead of its text")
+	flag.StringVar(&qrPNG, "qrpng", "", "write a QR code of the setup to this PNG file")
+	flag.IntVar(&delta, "delta", 0, "suggest card swaps that change the difficulty by about this much")
+
+	var err error
+
+	if err = validateFlags(args); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if quiet {
+		log.SetOutput(ioutil.Discard)
+	}
+	if verb {
+		fmt.Printf("Data version: %s\n", sentinels.DataVersion())
+	}
+
+	if hist != "" {
+		if sentinels.DefaultHistory, err = sentinels.LoadHistory(hist); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	if fitd {
+		sentinels.Model = sentinels.DefaultHistory.Fit(sentinels.DefaultPrior).Model()
+	}
+	if syn != "" {
+		data, err := ioutil.ReadFile(syn)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if sentinels.Synergies, err = sentinels.LoadSynergyMatrix(data); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	listenHooks(hooks)
+
+	ps, err := sentinels.LoadProfiles(profs)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	p := &sentinels.Params{Players: cfg.Players, LossPercent: cfg.LossPercent, Range: 10, Tolerance: 10}
+	if pre != "" {
+		if p = ps.Preset(pre); p == nil {
+			fmt.Printf("no preset named %q in %s\n", pre, profs)
+			return
+		}
+	} else if p.Expansions, err = sentinels.ParseExpansions(exps); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err = applyFlags(p); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if prof != "" {
+		pr := ps.Get(prof)
+		if pr == nil {
+			fmt.Printf("no profile named %q in %s\n", prof, profs)
+			return
+		}
+		pr.Apply(p)
+	}
+	if isFlagSet("players") {
+		p.Team = ps.Team(strings.Split(names, ","))
+	}
+	if save != "" {
+		if err = ps.SavePreset(save, p); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	if rplay != "" {
+		e := sentinels.DefaultHistory.Entry(rplay)
+		if e == nil || e.Seed == nil {
+			fmt.Printf("no replayable setup %q in the history\n", rplay)
+			return
+		}
+		s, err := sentinels.Replay(e.Seed)
+		if err != nil {
+			fmt.Println(err)
+		}
+		if s != nil {
+			fmt.Printf("\nReplayed:\n\n")
+			printSetup(s, 0)
+		}
+		return
+	}
+
+	if cover {
+		fmt.Printf("\n%s", sentinels.DefaultHistory.Coverage(p.CardSet()))
+		ss, err := sentinels.DefaultHistory.SuggestCoverage(p, 3)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		for n, s := range ss {
+			fmt.Printf("\nSuggestion %d:\n", n+1)
+			printSetup(s, 0)
+		}
+		return
+	}
+
+	if plan > 0 {
+		var lps []int
+		for _, f := range strings.Split(plps, ",") {
+			if f = strings.TrimSpace(f); f == "" {
+				continue
+			}
+			n, err := strconv.Atoi(f)
+			if err != nil || n < 1 || n > 99 {
+				fmt.Printf("bad loss percent %q in -planlp\n", f)
+				return
+			}
+			lps = append(lps, n)
+		}
+		ss, err := sentinels.Plan(p, plan, lps)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		var total time.Duration
+		for n, s := range ss {
+			total += s.Duration()
+			fmt.Printf("\nGame %d (about %v):\n", n+1, s.Duration())
+			printSetup(s, 0)
+		}
+		fmt.Printf("\nAbout %v in all.\n", total)
+		return
+	}
+
+	if pc2 != 0 {
+		a, b, err := sentinels.FindForTwoTables(p, p.Players, pc2)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("\nTable 1:\n")
+		printSetup(a, 0)
+		fmt.Printf("\nTable 2:\n")
+		printSetup(b, 0)
+		return
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	var s *sentinels.Setup
+	var i int
+	if draft > 0 {
+		s, i, err = runDraft(in, p)
+	} else {
+		s, i, err = sentinels.Find(p)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if s == nil {
+		fmt.Printf("\nNo setup found in %d iterations.\n", i)
+		return
+	}
+	printSetup(s, i)
+	if !veto {
+		return
+	}
+	for s.VetoesLeft > 0 {
+		fmt.Printf("\nVeto this setup (%d left)? [y/N] ", s.VetoesLeft)
+		line, _ := in.ReadString('\n')
+		if !strings.HasPrefix(strings.ToLower(strings.TrimSpace(line)), "y") {
+			return
+		}
+		if s, i, err = sentinels.Veto(s.Token); err != nil {
+			fmt.Println(err)
+			return
+		}
+		printSetup(s, i)
+	}
+}
+
+// runDraft deals hero choices, asks each player to pick one, and finds a
+// setup for the drafted team.
+func runDraft(in *bufio.Reader, p *sentinels.Params) (*sentinels.Setup, int, error) {
+	d, err := sentinels.DealDraft(p, draft)
+	if err != nil {
+		return nil, 0, err
+	}
+	players := strings.Split(names, ",")
+	var picks []*sentinels.Card
+	for n, offer := range d.Offers {
+		name := fmt.Sprintf("Player %d", n+1)
+		if n < len(players) && strings.TrimSpace(players[n]) != "" {
+			name = strings.TrimSpace(players[n])
+		}
+		fmt.Printf("\n%s, choose a hero:\n", name)
+		for j, c := range offer {
+			fmt.Printf("  %d. %s [%d]\n", j+1, c.DisplayName(lang), c.Points)
+		}
+		for {
+			fmt.Printf("> ")
+			line, err := in.ReadString('\n')
+			if j, cerr := strconv.Atoi(strings.TrimSpace(line)); cerr == nil && j >= 1 && j <= len(offer) {
+				picks = append(picks, offer[j-1])
+				break
+			}
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return sentinels.FinishDraft(p, picks)
+}
+
+func printSetup(s *sentinels.Setup, i int) {
+	s.Lang = lang
+	if quiet {
+		printBody(s)
+		return
+	}
+	if i > 0 {
+		fmt.Printf("\nFound in %d iterations:\n\n", i)
+	}
+	printBody(s)
+	fmt.Printf("%s\n", s.Describe())
+	fmt.Printf("Villain tier: %s\n", s.VillainTier())
+	for _, h := range s.Nemeses() {
+		fmt.Printf("Nemesis: %s faces %s\n", h.DisplayName(lang), s.Villain.DisplayName(lang))
+	}
+	if sy := s.Synergy; sy != nil {
+		fmt.Printf("Synergy: %+d", sy.Points)
+		if len(sy.Notes) > 0 {
+			fmt.Printf(" (%s)", strings.Join(sy.Notes, "; "))
+		}
+		fmt.Println()
+	}
+	for _, w := range s.Warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	if s.Seed != nil {
+		fmt.Printf("Seed: %d (%d draws)\n", s.Seed.Seed, s.Seed.Draws)
+	}
+	if s.Token != "" {
+		fmt.Printf("Token: %s\n", s.Token)
+	}
+	if verb {
+		printBreakdown(s)
+	}
+	if isFlagSet("players") {
+		if s.Seating == nil {
+			s.AssignSeats(strings.Split(names, ","))
+		}
+		fmt.Printf("\nTurn order:\n%s", s.SeatingText())
+	}
+	if check {
+		fmt.Printf("\nComponents:\n%s", s.Checklist())
+	}
+	if delta != 0 {
+		fmt.Printf("\nTo change the difficulty by %d:\n", delta)
+		for _, w := range s.Suggest(delta, 5) {
+			fmt.Printf("  %s\n", w)
+		}
+	}
+	if qrt || qrPNG != "" {
+		if err := printQR(s); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// printQR prints or saves a QR code of the setup's text or link.
+func printQR(s *sentinels.Setup) error {
+	text := s.PlainText()
+	if qrURL != "" {
+		text = strings.TrimSuffix(qrURL, "/") + "/score?" + s.ShareQuery()
+	}
+	c, err := qr.Encode(text)
+	if err != nil {
+		return err
+	}
+	if qrt {
+		fmt.Printf("\n%s", c.Text())
+	}
+	if qrPNG == "" {
+		return nil
+	}
+	f, err := os.Create(qrPNG)
+	if err != nil {
+		return err
+	}
+	if err := c.WritePNG(f, 8); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// printBody prints the setup itself in the chosen format.
+func printBody(s *sentinels.Setup) {
+	switch {
+	case plain:
+		fmt.Print(s.PlainText())
+	case table:
+		fmt.Print(s.Table(box))
+	default:
+		fmt.Printf("%s\n", s)
+	}
+}
+
+// printBreakdown prints where the setup's difficulty comes from and what
+// the search saw on the way to it.
+func printBreakdown(s *sentinels.Setup) {
+	fmt.Printf("\nDifficulty breakdown:\n")
+	for _, h := range s.Heroes {
+		fmt.Printf("  %-32s %4d\n", h.DisplayName(lang), s.HeroPoints(h))
+	}
+	fmt.Printf("  %-32s %4d\n", s.Villain.DisplayName(lang), s.VillainPoints())
+	fmt.Printf("  %-32s %4d\n", s.Environment.DisplayName(lang), s.Environment.Points)
+	fmt.Printf("  %-32s %4d\n", fmt.Sprintf("%d heroes", len(s.Heroes)), s.PcPoints)
+	fmt.Printf("  %-32s %4d (%d%% expected loss)\n", "Total", s.Difficulty, s.LossPercent)
+	if st := s.Stats; st != nil {
+		fmt.Printf("\nCandidates: %d scored, %d skipped as vetoed, %d the players wouldn't play, %d pitting a hero against their nemesis, %d with conflicts, %d not in the style, %d with too little synergy; difficulty %d to %d, mean %.1f\n",
+			st.Candidates, st.Excluded, st.Unseatable, st.Nemesis, st.Conflicts, st.Style, st.Synergy, st.Min, st.Max, st.Mean())
+	}
+}
+
+func validateFlags(args []string) error {
+	flag.CommandLine.Parse(args)
+	if pc < 3 || pc > 5 {
+		return errors.New("player count must be between 3 and 5.")
+	}
+
+	if lp < 1 || lp > 99 {
+		return errors.New("loss percentage must be between 1 and 99.")
+	}
+
+	if rg < 0 || rg > 100 {
+		return errors.New("range must be between 0 and 100.")
+	}
+
+	if pc2 != 0 && (pc2 < 3 || pc2 > 5) {
+		return errors.New("second table player count must be between 3 and 5.")
+	}
+
+	if tol < 0 || tol > 100 {
+		return errors.New("tolerance must be between 0 and 100.")
+	}
+
+	if level != "" {
+		if _, err := sentinels.ParseLevel(level); err != nil {
+			return err
+		}
+	}
+
+	if verb && quiet {
+		return errors.New("-v and -q can't be used together.")
+	}
+	return nil
+}
+
+// applyFlags overrides search parameters with the flags given on the
+// command line, reporting every flag with a bad value.
+func applyFlags(p *sentinels.Params) error {
+	var errs []error
+	flag.Visit(func(f *flag.Flag) {
+		var err error
+		switch f.Name {
+		case "pc":
+			p.Players = pc
+		case "lp":
+			p.LossPercent, p.ByTotal = lp, false
+		case "level":
+			p.LossPercent, err = sentinels.ParseLevel(level)
+			p.ByTotal = false
+		case "rg":
+			p.Range = rg
+		case "tt":
+			p.TargetTotal, p.ByTotal = tt, true
+		case "tol":
+			p.Tolerance = tol
+		case "exp":
+			p.Expansions, err = sentinels.ParseExpansions(exps)
+		case "heroexp":
+			p.HeroExpansions, err = sentinels.ParseExpansions(hexps)
+		case "villainexp":
+			p.VillainExpansions, err = sentinels.ParseExpansions(vexps)
+		case "envexp":
+			p.EnvExpansions, err = sentinels.ParseExpansions(eexps)
+		case "copies":
+			p.Copies, err = sentinels.ParseCopies(cops)
+		case "through":
+			var e sentinels.ExpansionType
+			if e, err = sentinels.ParseExpansion(thru); err == nil {
+				p.Expansions = sentinels.OwnedThrough(e)
+			}
+		case "exclude":
+			p.Exclude = excl
+		case "promo":
+			p.Promos = proms
+		case "excludevariants":
+			p.ExcludeVariants = xvar
+		case "pool":
+			p.Pools = pools
+		case "avoidpool":
+			p.AvoidPools = avoid
+		case "adv":
+			p.Advanced = adv
+		case "advmissing":
+			p.AllowMissingAdvanced = advm
+		case "confident":
+			p.HighConfidence = conf
+		case "tier":
+			p.Tier, err = sentinels.ParseTier(tier)
+		case "allowconflicts":
+			p.AllowConflicts = acon
+		case "nemesis":
+			p.Nemesis, err = sentinels.ParseNemesisMode(nem)
+		case "style":
+			p.Style, err = sentinels.ParseStyle(style)
+		case "minsynergy":
+			p.MinSynergy = minsy
+		case "seed":
+			p.Seed = seed
+		case "fair":
+			p.Fair = fair
+		case "teach":
+			p.Teaching = teach
+		case "maxiter":
+			p.MaxIterations = maxit
+		case "maxtime":
+			p.MaxDuration = maxt
+		case "family":
+			if fam {
+				p.ExcludeTags = append(p.ExcludeTags, sentinels.FamilyTags...)
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// listFlag is a flag that may be given more than once.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *listFlag) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// isFlagSet reports whether the named flag was given on the command line.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
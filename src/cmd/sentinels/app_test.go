@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+func TestApplyFlagsKeepsEarlierErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"-style", "bogus", "-tier", "hard"},
+		{"-nemesis", "bogus", "-style", "underdog"},
+		{"-through", "bogus", "-tier", "easy"},
+	} {
+		flag.CommandLine = flag.NewFlagSet("sentinels", flag.ContinueOnError)
+		flag.StringVar(&nem, "nemesis", "", "")
+		flag.StringVar(&style, "style", "", "")
+		flag.StringVar(&thru, "through", "", "")
+		flag.StringVar(&tier, "tier", "", "")
+		if err := flag.CommandLine.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		if err := applyFlags(&sentinels.Params{}); err == nil {
+			t.Errorf("%v: no error", args)
+		}
+	}
+}
@@ -0,0 +1,737 @@
+// Command sentinels is the command-line front end to the sentinels engine.
+// The engine is the module's root package and the web app is package
+// httpserver; both can be imported on their own.  Defaults, such as the
+// player count and expansions, come from a configuration file if there is
+// one; see package config.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/uhhhclem/sentinels"
+	"github.com/uhhhclem/sentinels/bots/scheduler"
+	"github.com/uhhhclem/sentinels/bots/webhook"
+	"github.com/uhhhclem/sentinels/config"
+)
+
+// command is a subcommand of the CLI.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{"generate", "generate [flags]: find a setup (the default; see generate -h)", func(args []string) error {
+			generate(args)
+			return nil
+		}},
+		{"score", "score -hero NAME... -villain NAME -env NAME [-adv]: score a given setup", score},
+		{"list", "list heroes|villains|environments|all [-exp LIST] [-table [-box]]: list cards and their points", list},
+		{"stats", "stats -hist FILE: print pick and win rates from a history", stats},
+		{"achievements", "achievements -hist FILE [-exp LIST]: print the group's progress toward its achievements", achievements},
+		{"leaderboard", "leaderboard -hist FILE [-profiles FILE]: rank the groups that have opted into the leaderboards", leaderboard},
+		{"fit", "fit -hist FILE [-prior N]: print card points fitted to the results in a history", fit},
+		{"scenario", "scenario [-hist FILE] [-entry N] [-pc N] [-lp N] [-exp LIST] FILE: find a setup for the next game of a scenario", scenario},
+		{"record", "record -hist FILE [-webhook URL]... TOKEN won|lost: record the result of a played setup", record},
+		{"note", "note -hist FILE [-rule RULE]... TOKEN [TEXT]: attach notes and house rules to a setup", note},
+		{"rotation", "rotation [-profiles FILE] [-exp LIST] [-reset hero|villain|environment|all] PROFILE: show or refill a group's rotations", rotation},
+		{"data", "data validate FILE | version | golden [FILE] | lint [FILE] | repair [-o OUT] FILE | check [-n N] [-seed S] | export FILE | import FILE: manage data", data},
+		{"schedule", "schedule [-hist FILE] [-profiles FILE] [-now JOB] CONFIG: post setups on the configured schedule", schedule},
+		{"players", "players list | add NAME [-fav HERO]... [-ban HERO]... [-comfort 1-3] | rm NAME: manage players", players},
+		{"completion", "completion bash|zsh|fish: print a shell completion script", completion},
+	}
+}
+
+func main() {
+	if err := sentinels.EnsureData(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := loadConfig(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer waitHooks()
+	args := os.Args[1:]
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		generate(args)
+		return
+	}
+	if args[0] == "__complete" {
+		if err := complete(args[1:]); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			if err := c.run(args[1:]); err != nil {
+				waitHooks()
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	fmt.Printf("unknown command %q; commands are:\n", args[0])
+	for _, c := range commands {
+		fmt.Printf("  %s\n", c.usage)
+	}
+	os.Exit(2)
+}
+
+// cfg holds the defaults read from the configuration file; see package
+// config.
+var cfg = config.Default()
+
+// loadConfig reads the configuration file, if there is one, and loads the
+// card data it names.
+func loadConfig() error {
+	var err error
+	if cfg, err = config.Load(""); err != nil {
+		return err
+	}
+	if cfg.Data == "" && len(cfg.Overlays) == 0 && cfg.Custom == "" {
+		return nil
+	}
+	data, overlays, err := readLayers()
+	if err != nil {
+		return err
+	}
+	if cfg.Custom != "" {
+		b, err := ioutil.ReadFile(cfg.Custom)
+		if err != nil {
+			return err
+		}
+		overlays = append(overlays, b)
+	}
+	return sentinels.LoadLayers(data, overlays...)
+}
+
+// readLayers reads the configured card data, which is nil if there is none,
+// and overlays, but not the custom cards.
+func readLayers() (data []byte, overlays [][]byte, err error) {
+	if cfg.Data != "" {
+		if data, err = ioutil.ReadFile(cfg.Data); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, path := range cfg.Overlays {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		overlays = append(overlays, b)
+	}
+	return data, overlays, nil
+}
+
+// score prints the difficulty of a setup given by name.
+func score(args []string) error {
+	fs := flag.NewFlagSet("score", flag.ExitOnError)
+	var heroes listFlag
+	fs.Var(&heroes, "hero", "name of a hero (repeat for each)")
+	villain := fs.String("villain", "", "name of the villain")
+	env := fs.String("env", "", "name of the environment")
+	adv := fs.Bool("adv", false, "play the villain in advanced mode")
+	fs.StringVar(&lang, "lang", "", "language for card names (e.g. es, de)")
+	fs.Parse(args)
+	s, err := sentinels.Score(heroes, *villain, *env, *adv)
+	if err != nil {
+		return err
+	}
+	s.Lang = lang
+	fmt.Printf("%s\n%s\n", s, s.Describe())
+	return nil
+}
+
+// list prints the cards of one type in the chosen expansions.
+func list(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("list what? heroes, villains or environments")
+	}
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	exp := fs.String("exp", strings.Join(sentinels.ExpansionNames, ","), "comma-separated expansions to list")
+	fs.StringVar(&lang, "lang", "", "language for card names (e.g. es, de)")
+	fs.BoolVar(&table, "table", false, "print the cards as an aligned table")
+	fs.BoolVar(&box, "box", false, "with -table, draw the table with box-drawing characters")
+	fs.Parse(args[1:])
+	e, err := sentinels.ParseExpansions(*exp)
+	if err != nil {
+		return err
+	}
+	cs := sentinels.Select(sentinels.WithExpansions(e...))
+	var cards []*sentinels.Card
+	switch args[0] {
+	case "heroes":
+		cards = cs.Heroes
+		cs = &sentinels.CardSet{Heroes: cards}
+	case "villains":
+		cards = cs.Villains
+		cs = &sentinels.CardSet{Villains: cards}
+	case "environments":
+		cards = cs.Environments
+		cs = &sentinels.CardSet{Environments: cards}
+	case "all":
+		if !table {
+			fmt.Print(cs)
+			return nil
+		}
+	default:
+		return fmt.Errorf("can't list %q; try heroes, villains, environments or all", args[0])
+	}
+	if table {
+		fmt.Print(cs.Table(lang, box))
+		return nil
+	}
+	for _, c := range cards {
+		fmt.Printf("%-32s %4d  %s\n", c.DisplayName(lang), c.Points, c.Expansion.Title())
+	}
+	return nil
+}
+
+// players lists, adds or removes registered players.
+func players(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: players list | add NAME [flags] | rm NAME")
+	}
+	fs := flag.NewFlagSet("players "+args[0], flag.ExitOnError)
+	fs.StringVar(&profs, "profiles", cfg.Storage.Profiles, "file containing saved profiles, presets and players")
+	var favs, bans listFlag
+	fs.Var(&favs, "fav", "a favorite hero (may be repeated)")
+	fs.Var(&bans, "ban", "a hero the player won't play (may be repeated)")
+	comfort := fs.Int("comfort", 0, "most complex hero the player is happy with, 1-3 (0 for any)")
+	var name string
+	if len(args) > 1 && !strings.HasPrefix(args[1], "-") {
+		name = args[1]
+		fs.Parse(args[2:])
+	} else {
+		fs.Parse(args[1:])
+	}
+	ps, err := sentinels.LoadProfiles(profs)
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "list":
+		for _, n := range ps.PlayerNames() {
+			pl := ps.Player(n)
+			fmt.Printf("%s: comfort %d; favorites: %s; won't play: %s\n", pl.Name, pl.Comfort,
+				strings.Join(pl.Favorites, ", "), strings.Join(pl.Banned, ", "))
+		}
+		return nil
+	case "add":
+		if name == "" {
+			return fmt.Errorf("usage: players add NAME [flags]")
+		}
+		if *comfort < 0 || *comfort > 3 {
+			return fmt.Errorf("comfort must be between 0 and 3")
+		}
+		for _, h := range append(append([]string(nil), favs...), bans...) {
+			if c, ok := sentinels.Lookup(h); !ok || c.Type != sentinels.Hero {
+				return fmt.Errorf("unknown hero %q", h)
+			}
+		}
+		return ps.PutPlayer(&sentinels.Player{Name: name, Favorites: favs, Banned: bans, Comfort: *comfort})
+	case "rm":
+		if name == "" || ps.Player(name) == nil {
+			return fmt.Errorf("no player named %q", name)
+		}
+		return ps.RemovePlayer(ps.Player(name).Name)
+	}
+	return fmt.Errorf("unknown players command %q", args[0])
+}
+
+// webhooks posts the CLI's events to the URLs given with -webhook.
+var webhooks *webhook.Hooks
+
+// listenHooks starts posting events to the given URLs, if any.
+func listenHooks(urls []string) {
+	if len(urls) > 0 {
+		webhooks = &webhook.Hooks{URLs: urls}
+		webhooks.Listen()
+	}
+}
+
+// waitHooks waits for posts to the webhooks to finish.
+func waitHooks() {
+	if webhooks != nil {
+		webhooks.Wait()
+	}
+}
+
+// loadHistory loads the history named by a -hist flag.
+func loadHistory(path string) error {
+	if path == "" {
+		return fmt.Errorf("-hist is required")
+	}
+	var err error
+	sentinels.DefaultHistory, err = sentinels.LoadHistory(path)
+	return err
+}
+
+// schedule runs the jobs in a scheduler configuration until interrupted,
+// or one job at once.
+func schedule(args []string) error {
+	var now string
+	fs := flag.NewFlagSet("schedule", flag.ExitOnError)
+	fs.StringVar(&hist, "hist", cfg.Storage.History, "file holding the history of generated setups")
+	fs.StringVar(&profs, "profiles", cfg.Storage.Profiles, "file containing saved profiles and presets")
+	fs.StringVar(&now, "now", "", "run this job once, now, and exit")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: schedule [-hist FILE] [-profiles FILE] [-now JOB] CONFIG")
+	}
+	c, err := scheduler.LoadConfig(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if hist != "" {
+		if err := loadHistory(hist); err != nil {
+			return err
+		}
+	}
+	ps, err := sentinels.LoadProfiles(profs)
+	if err != nil {
+		return err
+	}
+	sc := scheduler.New(c, ps)
+	if now != "" {
+		j := c.Find(now)
+		if j == nil {
+			return fmt.Errorf("no job named %q in %s", now, fs.Arg(0))
+		}
+		return sc.Run(j)
+	}
+	sc.Start()
+	select {}
+}
+
+// stats prints the pick and win rates of the cards in a history.
+func stats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	fs.StringVar(&hist, "hist", cfg.Storage.History, "file holding the history of generated setups")
+	fs.Parse(args)
+	if err := loadHistory(hist); err != nil {
+		return err
+	}
+	st := sentinels.DefaultHistory.Stats()
+	fmt.Printf("%d setups\n\n", st.Setups)
+	for _, c := range st.Cards {
+		fmt.Printf("%-32s %4d picks %4.0f%%", c.Name, c.Picks, c.PickRate*100)
+		if c.Games > 0 {
+			fmt.Printf("  won %d of %d (%.0f%%)", c.Wins, c.Games, c.WinRate*100)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// achievements prints the group's progress toward its achievements.
+func achievements(args []string) error {
+	fs := flag.NewFlagSet("achievements", flag.ExitOnError)
+	fs.StringVar(&hist, "hist", cfg.Storage.History, "file holding the history of generated setups")
+	exp := fs.String("exp", strings.Join(cfg.Expansions, ","), "comma-separated expansions in the collection")
+	fs.Parse(args)
+	if err := loadHistory(hist); err != nil {
+		return err
+	}
+	el, err := sentinels.ParseExpansions(*exp)
+	if err != nil {
+		return err
+	}
+	for _, a := range sentinels.DefaultHistory.Achievements(sentinels.Select(sentinels.WithExpansions(el...))) {
+		status := fmt.Sprintf("%d of %d", a.Progress, a.Goal)
+		if a.Done() {
+			status = "earned " + a.Earned.Format("2006-01-02")
+		}
+		fmt.Printf("%-20s %-18s %s\n", a.Name, status, a.Description)
+	}
+	return nil
+}
+
+// leaderboard prints the leaderboards of the groups in the profiles file
+// that have opted in.
+func leaderboard(args []string) error {
+	fs := flag.NewFlagSet("leaderboard", flag.ExitOnError)
+	fs.StringVar(&hist, "hist", cfg.Storage.History, "file holding the history of generated setups")
+	fs.StringVar(&profs, "profiles", cfg.Storage.Profiles, "file containing saved profiles and presets")
+	fs.Parse(args)
+	if err := loadHistory(hist); err != nil {
+		return err
+	}
+	ps, err := sentinels.LoadProfiles(profs)
+	if err != nil {
+		return err
+	}
+	lb := sentinels.DefaultHistory.Leaderboard(ps)
+	fmt.Println("Hardest setup beaten")
+	for i, st := range lb.Hardest {
+		fmt.Printf("%2d. %-24s %3d%%  won %d of %d\n", i+1, st.Group, st.Hardest, st.Wins, st.Games)
+	}
+	fmt.Println("\nBest calibrated")
+	for i, st := range lb.Calibration {
+		fmt.Printf("%2d. %-24s %.3f  %d games\n", i+1, st.Group, st.Calibration, st.Games)
+	}
+	return nil
+}
+
+// fit prints the cards' points fitted to the group's results.
+func fit(args []string) error {
+	fs := flag.NewFlagSet("fit", flag.ExitOnError)
+	fs.StringVar(&hist, "hist", cfg.Storage.History, "file holding the history of generated setups")
+	prior := fs.Float64("prior", sentinels.DefaultPrior, "how far, in points, a card's points may be expected to be off")
+	fs.Parse(args)
+	if err := loadHistory(hist); err != nil {
+		return err
+	}
+	f := sentinels.DefaultHistory.Fit(*prior)
+	fmt.Printf("%d games\n\n", f.Games)
+	for _, c := range f.Cards {
+		fmt.Printf("%-32s %4d -> %4d  won %d of %d\n", c.Name, c.Points, c.Fitted, c.Wins, c.Games)
+	}
+	return nil
+}
+
+// scenario finds a setup for an entry of a scenario: the one given by
+// -entry, or else the next one the history says the group hasn't won.
+func scenario(args []string) error {
+	fs := flag.NewFlagSet("scenario", flag.ExitOnError)
+	fs.StringVar(&hist, "hist", cfg.Storage.History, "file holding the history of generated setups")
+	entry := fs.Int("entry", 0, "number of the entry to play, counting from 1; 0 means the next one")
+	pc := fs.Int("pc", cfg.Players, "number of heroes")
+	lp := fs.Int("lp", cfg.LossPercent, "target loss percentage")
+	rg := fs.Int("rg", 10, "allowable difficulty variance")
+	exp := fs.String("exp", strings.Join(cfg.Expansions, ","), "comma-separated expansions to use")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: scenario [-hist FILE] [-entry N] [-pc N] [-lp N] [-exp LIST] FILE")
+	}
+	sc, err := sentinels.LoadScenario(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if hist != "" {
+		if err := loadHistory(hist); err != nil {
+			return err
+		}
+	}
+	i := *entry - 1
+	if *entry == 0 {
+		if i = sc.Next(sentinels.DefaultHistory); i == len(sc.Entries) {
+			fmt.Printf("%s is complete.\n", sc.Name)
+			return nil
+		}
+	}
+	el, err := sentinels.ParseExpansions(*exp)
+	if err != nil {
+		return err
+	}
+	log.SetOutput(ioutil.Discard)
+	s, _, err := sc.Play(i, &sentinels.Params{Players: *pc, LossPercent: *lp, Range: *rg, Expansions: el})
+	if err != nil {
+		return err
+	}
+	e := sc.Entries[i]
+	if i == 0 && sc.Intro != "" {
+		fmt.Printf("%s\n\n%s\n\n", sc.Name, sc.Intro)
+	}
+	fmt.Printf("%d of %d: %s\n", i+1, len(sc.Entries), e.Title)
+	if e.Text != "" {
+		fmt.Printf("%s\n", e.Text)
+	}
+	fmt.Printf("\n%s\n%s\n", s, s.Describe())
+	if hist != "" {
+		fmt.Printf("Token: %s\n", s.Token)
+	}
+	return nil
+}
+
+// record records whether the heroes won a setup in the history.
+func record(args []string) error {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	fs.StringVar(&hist, "hist", cfg.Storage.History, "file holding the history of generated setups")
+	fs.Var(&hooks, "webhook", "URL to post the result to as JSON (may be repeated)")
+	fs.Parse(args)
+	listenHooks(hooks)
+	if fs.NArg() != 2 || (fs.Arg(1) != sentinels.Won && fs.Arg(1) != sentinels.Lost) {
+		return fmt.Errorf("usage: record -hist FILE TOKEN won|lost")
+	}
+	if err := loadHistory(hist); err != nil {
+		return err
+	}
+	return sentinels.DefaultHistory.RecordResult(fs.Arg(0), fs.Arg(1) == sentinels.Won)
+}
+
+// note attaches notes and house rules to a setup in the history.
+func note(args []string) error {
+	var rules listFlag
+	fs := flag.NewFlagSet("note", flag.ExitOnError)
+	fs.StringVar(&hist, "hist", cfg.Storage.History, "file holding the history of generated setups")
+	fs.Var(&rules, "rule", "house rule played with (may be repeated)")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		return fmt.Errorf("usage: note -hist FILE [-rule RULE]... TOKEN [TEXT]")
+	}
+	if err := loadHistory(hist); err != nil {
+		return err
+	}
+	return sentinels.DefaultHistory.Annotate(fs.Arg(0), strings.Join(fs.Args()[1:], " "), rules)
+}
+
+// rotation prints what is left of a group's rotations, after refilling
+// one or all of them if asked to.
+func rotation(args []string) error {
+	fs := flag.NewFlagSet("rotation", flag.ExitOnError)
+	fs.StringVar(&profs, "profiles", cfg.Storage.Profiles, "file containing saved profiles and presets")
+	exp := fs.String("exp", strings.Join(cfg.Expansions, ","), "comma-separated expansions in the collection, unless the profile lists some")
+	reset := fs.String("reset", "", "rotation to refill: hero, villain, environment or all")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: rotation [-profiles FILE] [-exp LIST] [-reset TYPE] PROFILE")
+	}
+	ps, err := sentinels.LoadProfiles(profs)
+	if err != nil {
+		return err
+	}
+	name := fs.Arg(0)
+	if ps.Get(name) == nil {
+		return fmt.Errorf("no profile named %q", name)
+	}
+	types := []sentinels.CardType{sentinels.Hero, sentinels.Villain, sentinels.Environment}
+	typeNames := []string{"hero", "villain", "environment"}
+	if *reset != "" {
+		found := false
+		for i, t := range types {
+			if *reset == typeNames[i] || *reset == "all" {
+				found = true
+				if err := ps.ResetBag(name, t); err != nil {
+					return err
+				}
+			}
+		}
+		if !found {
+			return fmt.Errorf("-reset must be hero, villain, environment or all, not %q", *reset)
+		}
+	}
+	p := &sentinels.Params{}
+	if p.Expansions, err = sentinels.ParseExpansions(*exp); err != nil {
+		return err
+	}
+	pr := ps.Get(name)
+	pr.Apply(p)
+	p.Bag = nil
+	cs := p.CardSet()
+	for i, t := range types {
+		b := pr.Bag(t, cs)
+		state := "off"
+		if b.On {
+			state = "on"
+		}
+		fmt.Printf("%s rotation (%s): %d left, %d drawn\n", typeNames[i], state, len(b.Left), len(b.Drawn))
+		if len(b.Left) > 0 {
+			fmt.Printf("  left: %s\n", strings.Join(b.Left, ", "))
+		}
+	}
+	return nil
+}
+
+// data validates card data, reports the data version, or exports or
+// imports the profiles, history and custom cards.
+func data(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: data validate FILE | version | golden [FILE] | lint [FILE] | repair [-o OUT] FILE | check [-n N] [-seed S] | export FILE | import FILE")
+	}
+	var n int
+	var seed int64
+	var out string
+	fs := flag.NewFlagSet("data "+args[0], flag.ExitOnError)
+	fs.StringVar(&out, "o", "", "with repair, file to write the repaired data to instead of standard output")
+	fs.IntVar(&n, "n", 1000, "with check, number of random searches to run")
+	fs.Int64Var(&seed, "seed", 1, "with check, seed for the random search parameters")
+	fs.StringVar(&hist, "hist", cfg.Storage.History, "file holding the history of generated setups")
+	fs.StringVar(&profs, "profiles", cfg.Storage.Profiles, "file containing saved profiles and presets")
+	fs.Parse(args[1:])
+	switch args[0] {
+	case "version":
+		fmt.Println(sentinels.DataVersion())
+		return nil
+	case "validate":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: data validate FILE")
+		}
+		return validateFile(fs.Arg(0))
+	case "golden":
+		if fs.NArg() > 1 {
+			return fmt.Errorf("usage: data golden [FILE]")
+		}
+		return checkFile(fs.Arg(0), sentinels.CheckGolden)
+	case "lint":
+		if fs.NArg() > 1 {
+			return fmt.Errorf("usage: data lint [FILE]")
+		}
+		return checkFile(fs.Arg(0), sentinels.LintData)
+	case "repair":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: data repair [-o OUT] FILE")
+		}
+		return repairFile(fs.Arg(0), out)
+	case "check":
+		log.SetOutput(ioutil.Discard)
+		found, err := sentinels.CheckGeneration(n, seed)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d searches, %d setups found, all valid\n", n, found)
+		return nil
+	case "export", "import":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: data %s [-hist FILE] [-profiles FILE] FILE", args[0])
+		}
+		if err := loadHistory(hist); err != nil {
+			return err
+		}
+		ps, err := sentinels.LoadProfiles(profs)
+		if err != nil {
+			return err
+		}
+		if args[0] == "export" {
+			return exportFile(fs.Arg(0), ps)
+		}
+		return importFile(fs.Arg(0), ps)
+	}
+	return fmt.Errorf("unknown data command %q", args[0])
+}
+
+// validateFile checks a card data file, which may hold the full data or
+// just its "difficulty" field, and prints any problems.
+func validateFile(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var full struct{ Difficulty sentinels.DifficultyData }
+	if err := json.Unmarshal(b, &full); err != nil {
+		return err
+	}
+	dd := &full.Difficulty
+	if len(dd.Hero)+len(dd.Villain)+len(dd.Env) == 0 {
+		dd = &sentinels.DifficultyData{}
+		if err := json.Unmarshal(b, dd); err != nil {
+			return err
+		}
+	}
+	return printDiagnostics(path, sentinels.ValidateCards(dd))
+}
+
+// checkFile checks a full data file, or the built-in data if path is
+// empty, with check, such as CheckGolden, and prints any problems.
+func checkFile(path string, check func([]byte) []sentinels.Diagnostic) error {
+	if path == "" {
+		return printDiagnostics("built-in data", check(nil))
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return printDiagnostics(path, check(b))
+}
+
+// repairFile sorts and repairs the scale of a full data file, writing the
+// result to out or, if out is empty, to standard output.
+func repairFile(path, out string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if b, err = sentinels.RepairData(b); err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(append(b, '\n'))
+		return err
+	}
+	return ioutil.WriteFile(out, append(b, '\n'), 0644)
+}
+
+// printDiagnostics prints diagnostics about what, errors last, and returns
+// an error if there are any errors.
+func printDiagnostics(what string, ds []sentinels.Diagnostic) error {
+	sort.SliceStable(ds, func(i, j int) bool { return ds[i].Severity != "error" && ds[j].Severity == "error" })
+	errs := 0
+	for _, d := range ds {
+		fmt.Println(d)
+		if d.Severity == "error" {
+			errs++
+		}
+	}
+	if errs > 0 {
+		return fmt.Errorf("%s: %d errors", what, errs)
+	}
+	fmt.Printf("%s: OK\n", what)
+	return nil
+}
+
+// exportFile writes the profiles, history and custom cards to an archive.
+func exportFile(path string, ps *sentinels.Profiles) error {
+	var custom []byte
+	if cfg.Custom != "" {
+		b, err := ioutil.ReadFile(cfg.Custom)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		custom = b
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = sentinels.Export(f, ps, sentinels.DefaultHistory, custom); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// importFile replaces the profiles, history and custom cards with those in
+// an archive.
+func importFile(path string, ps *sentinels.Profiles) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return sentinels.Import(f, fi.Size(), ps, sentinels.DefaultHistory, restoreCustom)
+}
+
+// restoreCustom checks imported custom cards against the configured data
+// and overlays and writes them to the custom cards file.
+func restoreCustom(custom []byte) error {
+	if cfg.Custom == "" {
+		return errors.New("no custom cards file is configured")
+	}
+	data, overlays, err := readLayers()
+	if err != nil {
+		return err
+	}
+	if err := sentinels.LoadLayers(data, append(overlays, custom)...); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(cfg.Custom, custom, 0644)
+}
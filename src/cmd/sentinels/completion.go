@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// completion prints a shell completion script.  The scripts ask the CLI
+// itself for commands and card names (see complete), so they stay current
+// with the CLI and the data.
+func completion(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: completion bash|zsh|fish")
+	}
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		return fmt.Errorf("no completion for %q; try bash, zsh or fish", args[0])
+	}
+	fmt.Print(script)
+	return nil
+}
+
+// complete prints the names of one kind of thing, one per line, for the
+// completion scripts.
+func complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: __complete commands|cards|heroes|villains|environments|expansions|pools")
+	}
+	var names []string
+	switch args[0] {
+	case "commands":
+		for _, c := range commands {
+			names = append(names, c.name)
+		}
+	case "expansions":
+		names = sentinels.ExpansionNames
+	case "pools":
+		names = sentinels.EnvironmentPools()
+	default:
+		want := map[string]sentinels.CardType{"heroes": sentinels.Hero, "villains": sentinels.Villain, "environments": sentinels.Environment}
+		t, typed := want[args[0]]
+		if !typed && args[0] != "cards" {
+			return fmt.Errorf("can't complete %q", args[0])
+		}
+		for _, c := range sentinels.AllCards() {
+			if !typed || c.Type == t {
+				names = append(names, c.Name)
+			}
+		}
+		sort.Strings(names)
+	}
+	for _, n := range names {
+		fmt.Println(n)
+	}
+	return nil
+}
+
+var completionScripts = map[string]string{
+	"bash": `# bash completion for sentinels; load with: source <(sentinels completion bash)
+_sentinels() {
+	local cur prev kind pre name
+	cur="${COMP_WORDS[COMP_CWORD]}"
+	prev="${COMP_WORDS[COMP_CWORD-1]}"
+	case "$prev" in
+	-exclude|--exclude) kind=cards ;;
+	-hero|--hero) kind=heroes ;;
+	-villain|--villain) kind=villains ;;
+	-env|--env) kind=environments ;;
+	-exp|--exp|-through|--through) kind=expansions ;;
+	-pool|--pool|-avoidpool|--avoidpool) kind=pools ;;
+	*)
+		if [ "$COMP_CWORD" -eq 1 ]; then
+			COMPREPLY=($(compgen -W "$(sentinels __complete commands)" -- "$cur"))
+		fi
+		return ;;
+	esac
+	cur="${cur//\\/}"
+	if [ "$kind" = expansions ] && [[ "$cur" == *,* ]]; then
+		pre="${cur%,*},"
+		cur="${cur##*,}"
+	fi
+	local IFS=$'\n'
+	COMPREPLY=()
+	for name in $(sentinels __complete "$kind"); do
+		case "$name" in
+		"$cur"*) COMPREPLY+=("$(printf '%q' "$pre$name")") ;;
+		esac
+	done
+}
+complete -o default -F _sentinels sentinels
+`,
+	"zsh": `#compdef sentinels
+# zsh completion for sentinels; load with: source <(sentinels completion zsh)
+_sentinels() {
+	local kind
+	case "${words[CURRENT-1]}" in
+	-exclude|--exclude) kind=cards ;;
+	-hero|--hero) kind=heroes ;;
+	-villain|--villain) kind=villains ;;
+	-env|--env) kind=environments ;;
+	-exp|--exp|-through|--through) kind=expansions ;;
+	-pool|--pool|-avoidpool|--avoidpool) kind=pools ;;
+	*)
+		if (( CURRENT == 2 )); then
+			compadd ${(f)"$(sentinels __complete commands)"}
+		else
+			_files
+		fi
+		return ;;
+	esac
+	local -a names
+	names=("${(@f)$(sentinels __complete $kind)}")
+	if [[ $kind == expansions ]]; then
+		compset -P '*,'
+	fi
+	compadd -a names
+}
+compdef _sentinels sentinels
+`,
+	"fish": `# fish completion for sentinels; load with: sentinels completion fish | source
+complete -c sentinels -f -n __fish_use_subcommand -a '(sentinels __complete commands)'
+complete -c sentinels -o exclude -x -a '(sentinels __complete cards)'
+complete -c sentinels -o hero -x -a '(sentinels __complete heroes)'
+complete -c sentinels -o villain -x -a '(sentinels __complete villains)'
+complete -c sentinels -o env -x -a '(sentinels __complete environments)'
+complete -c sentinels -o exp -x -a '(sentinels __complete expansions)'
+complete -c sentinels -o through -x -a '(sentinels __complete expansions)'
+complete -c sentinels -o pool -x -a '(sentinels __complete pools)'
+complete -c sentinels -o avoidpool -x -a '(sentinels __complete pools)'
+`,
+}
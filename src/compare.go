@@ -0,0 +1,79 @@
+package sentinels
+
+// Breakdown is a setup's difficulty split by where the points come from.
+type Breakdown struct {
+	Heroes      int
+	Villain     int
+	Environment int
+	Players     int // the player count modifier
+	Total       int
+}
+
+// Breakdown returns the points each part of the setup contributes.  Total
+// is the setup's difficulty, which may differ from the sum of the parts if
+// Model isn't the points model.
+func (s *Setup) Breakdown() Breakdown {
+	b := Breakdown{
+		Villain:     s.VillainPoints(),
+		Environment: s.Environment.Points,
+		Players:     s.PcPoints,
+		Total:       s.Difficulty,
+	}
+	for _, h := range s.Heroes {
+		b.Heroes += s.HeroPoints(h)
+	}
+	return b
+}
+
+// Comparison sets two setups side by side.
+type Comparison struct {
+	A, B            *Setup
+	APoints         Breakdown
+	BPoints         Breakdown
+	SharedHeroes    []*Card // heroes in both setups, in A's order
+	OnlyA, OnlyB    []*Card // heroes in just one
+	SameVillain     bool
+	SameEnvironment bool
+	Change          Breakdown // B's points less A's
+	LossDelta       int       // B's expected loss percentage less A's
+}
+
+// Compare compares two setups, such as alternatives a group is choosing
+// between.
+func Compare(a, b *Setup) *Comparison {
+	c := &Comparison{
+		A:               a,
+		B:               b,
+		APoints:         a.Breakdown(),
+		BPoints:         b.Breakdown(),
+		SameVillain:     a.Villain.Name == b.Villain.Name && a.Advanced == b.Advanced,
+		SameEnvironment: a.Environment.Name == b.Environment.Name,
+		LossDelta:       b.LossPercent - a.LossPercent,
+	}
+	c.Change = Breakdown{
+		Heroes:      c.BPoints.Heroes - c.APoints.Heroes,
+		Villain:     c.BPoints.Villain - c.APoints.Villain,
+		Environment: c.BPoints.Environment - c.APoints.Environment,
+		Players:     c.BPoints.Players - c.APoints.Players,
+		Total:       c.BPoints.Total - c.APoints.Total,
+	}
+	inB := make(map[string]bool)
+	for _, h := range b.Heroes {
+		inB[h.Name] = true
+	}
+	inA := make(map[string]bool)
+	for _, h := range a.Heroes {
+		inA[h.Name] = true
+		if inB[h.Name] {
+			c.SharedHeroes = append(c.SharedHeroes, h)
+		} else {
+			c.OnlyA = append(c.OnlyA, h)
+		}
+	}
+	for _, h := range b.Heroes {
+		if !inA[h.Name] {
+			c.OnlyB = append(c.OnlyB, h)
+		}
+	}
+	return c
+}
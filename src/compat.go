@@ -0,0 +1,47 @@
+package sentinels
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// The functions in this file are the package's first API, kept so that
+// existing programs build and behave as before.  Each logs a notice the
+// first time it is called.
+
+var deprecatedSeen sync.Map
+
+// deprecated logs, once per name, that a deprecated function was called.
+func deprecated(name, use string) {
+	if _, seen := deprecatedSeen.LoadOrStore(name, true); !seen {
+		log.Printf("%s is deprecated; use %s instead.", name, use)
+	}
+}
+
+// GetCardSet builds a CardSet containing all cards in the selected expansions.
+//
+// Deprecated: Use Select(WithExpansions(exp...)).
+func GetCardSet(exp []ExpansionType) *CardSet {
+	deprecated("GetCardSet", "Select")
+	return Select(WithExpansions(exp...))
+}
+
+// FindSetup finds a setup given a player count, loss percentage, range,
+// and set of expansions.
+//
+// Deprecated: Use Generate with WithPlayers, WithLossPercent and
+// WithExpansions.
+func FindSetup(pc, lp, rg int, exp []ExpansionType) (*Setup, int, error) {
+	deprecated("FindSetup", "Generate")
+	return Generate(context.Background(), WithPlayers(pc), WithLossPercent(lp, rg), WithExpansions(exp...))
+}
+
+// FindSetupByTotal finds a setup whose difficulty is within tol points of
+// the target total tt, bypassing the loss percentage conversion.
+//
+// Deprecated: Use Generate with WithPlayers, WithTotal and WithExpansions.
+func FindSetupByTotal(pc, tt, tol int, exp []ExpansionType) (*Setup, int, error) {
+	deprecated("FindSetupByTotal", "Generate")
+	return Generate(context.Background(), WithPlayers(pc), WithTotal(tt, tol), WithExpansions(exp...))
+}
@@ -0,0 +1,58 @@
+package sentinels
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// ComponentGroup lists the components to fetch from one box.
+type ComponentGroup struct {
+	Expansion ExpansionType
+	Items     []string
+}
+
+// Components lists the physical components a setup needs, grouped by the
+// box they are stored in, in expansion order.  Promo variants need both
+// their base deck and the promo character card.
+func (s *Setup) Components() []ComponentGroup {
+	items := make(map[ExpansionType][]string)
+	add := func(c *Card, what string) {
+		deck := c
+		if b, ok := Lookup(c.Base); ok {
+			deck = b
+		}
+		items[deck.Expansion] = append(items[deck.Expansion], fmt.Sprintf("%s %s", deck.Name, what))
+		if deck != c {
+			items[c.Expansion] = append(items[c.Expansion], fmt.Sprintf("%s character card", c.Name))
+		}
+	}
+	for _, h := range s.Heroes {
+		add(h, "hero deck")
+	}
+	if s.Advanced {
+		add(s.Villain, "villain deck (advanced side)")
+	} else {
+		add(s.Villain, "villain deck")
+	}
+	add(s.Environment, "environment deck")
+
+	var groups []ComponentGroup
+	for e, l := range items {
+		groups = append(groups, ComponentGroup{e, l})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Expansion < groups[j].Expansion })
+	return groups
+}
+
+// Checklist formats the setup's components as a list grouped by box.
+func (s *Setup) Checklist() string {
+	var b bytes.Buffer
+	for _, g := range s.Components() {
+		fmt.Fprintf(&b, "%s:\n", g.Expansion.Title())
+		for _, i := range g.Items {
+			fmt.Fprintf(&b, "   [ ] %s\n", i)
+		}
+	}
+	return b.String()
+}
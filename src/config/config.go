@@ -0,0 +1,142 @@
+// Package config reads the settings the sentinels command and web app
+// share from a TOML file, so that a group's usual player count, collection
+// and files needn't be given every time.  A file looks like:
+//
+//	players = 4
+//	loss_percent = 60
+//	expansions = ["baseset", "rookcity", "infernalrelics"]
+//	data = "cards.json"    # card data to load in place of the built-in data
+//	overlays = ["promos.json", "house.json"] # applied in order on top of it
+//	custom = "custom.json" # cards to add last
+//	addr = ":8080"         # the web app's listen address
+//	templates = "templates"
+//
+//	[storage]
+//	backend = "file"       # "file", "memory" or "sql"
+//	profiles = "profiles.json"
+//	history = "history.json"
+//	driver = "sqlite3"     # for "sql": a database/sql driver linked into the program
+//	dsn = "sentinels.db"
+//
+// Flags and environment variables override the file's values.
+package config
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/uhhhclem/sentinels"
+	"github.com/uhhhclem/sentinels/storage"
+)
+
+// DefaultPath is the file Load reads when no other is named.
+const DefaultPath = "sentinels.toml"
+
+// Config holds the shared settings.
+type Config struct {
+	Players     int      `toml:"players"`
+	LossPercent int      `toml:"loss_percent"`
+	Expansions  []string `toml:"expansions"`
+	Data        string   `toml:"data"`
+	Overlays    []string `toml:"overlays"`
+	Custom      string   `toml:"custom"`
+	Addr        string   `toml:"addr"`
+	Templates   string   `toml:"templates"`
+	Storage     Storage  `toml:"storage"`
+}
+
+// Storage says where profiles, presets and history are kept.
+type Storage struct {
+	Backend  string `toml:"backend"`
+	Profiles string `toml:"profiles"`
+	History  string `toml:"history"`
+	Driver   string `toml:"driver"`
+	DSN      string `toml:"dsn"`
+}
+
+// Default returns the settings used where the file doesn't give any.
+func Default() *Config {
+	return &Config{
+		Players:     3,
+		LossPercent: 50,
+		Expansions:  []string{"baseset", "miniexpansion"},
+		Addr:        ":8080",
+		Storage:     Storage{Backend: "file", Profiles: "profiles.json"},
+	}
+}
+
+// Load reads the file at path over the defaults.  If path is empty, it is
+// taken from $SENTINELS_CONFIG, or is DefaultPath if that file exists; with
+// no file, Load returns the defaults.
+func Load(path string) (*Config, error) {
+	c := Default()
+	if path == "" {
+		path = os.Getenv("SENTINELS_CONFIG")
+	}
+	if path == "" {
+		if _, err := os.Stat(DefaultPath); err != nil {
+			return c, nil
+		}
+		path = DefaultPath
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := decode(string(b), c); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := c.check(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return c, nil
+}
+
+// check returns an error if a setting is out of range.
+func (c *Config) check() error {
+	if c.Players < 3 || c.Players > 5 {
+		return fmt.Errorf("Players must be 3 to 5, not %d.", c.Players)
+	}
+	if c.LossPercent < 1 || c.LossPercent > 99 {
+		return fmt.Errorf("Loss_percent must be 1 to 99, not %d.", c.LossPercent)
+	}
+	if _, err := c.ExpansionTypes(); err != nil {
+		return err
+	}
+	switch c.Storage.Backend {
+	case "file", "memory":
+	case "sql":
+		if c.Storage.Driver == "" {
+			return errors.New("The sql storage backend needs a driver.")
+		}
+	default:
+		return fmt.Errorf("Unknown storage backend %q.", c.Storage.Backend)
+	}
+	return nil
+}
+
+// ExpansionTypes returns the configured expansions, with any bundles
+// among them expanded; see sentinels.ParseExpansions.
+func (c *Config) ExpansionTypes() ([]sentinels.ExpansionType, error) {
+	return sentinels.ParseExpansions(strings.Join(c.Expansions, ","))
+}
+
+// Open opens the configured store.  The sql backend needs the program to
+// link in the named driver.
+func (s *Storage) Open() (sentinels.Store, error) {
+	switch s.Backend {
+	case "memory":
+		return &storage.MemoryStore{}, nil
+	case "sql":
+		db, err := sql.Open(s.Driver, s.DSN)
+		if err != nil {
+			return nil, err
+		}
+		return storage.NewSQLStore(db)
+	}
+	return &sentinels.FileStore{ProfilesPath: s.Profiles, HistoryPath: s.History}, nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		want func(c *Config)
+		err  string // part of the error, if one is wanted
+	}{
+		{text: `players = 4`, want: func(c *Config) { c.Players = 4 }},
+		{text: `loss_percent = 6_0 # sixty`, want: func(c *Config) { c.LossPercent = 60 }},
+		{text: `data = "cards # one, two.json" # the data`, want: func(c *Config) { c.Data = "cards # one, two.json" }},
+		{text: `data = 'C:\cards#1.json'`, want: func(c *Config) { c.Data = `C:\cards#1.json` }},
+		{text: `custom = "say \"hi\" # not a comment"`, want: func(c *Config) { c.Custom = `say "hi" # not a comment` }},
+		{text: `expansions = ["baseset", "rookcity",]`, want: func(c *Config) { c.Expansions = []string{"baseset", "rookcity"} }},
+		{text: `overlays = ["a,b.json", 'c#d.json']`, want: func(c *Config) { c.Overlays = []string{"a,b.json", "c#d.json"} }},
+		{text: "overlays = [\n\t\"a.json\", # first\n\t\"b.json\"\n]\nplayers = 5", want: func(c *Config) {
+			c.Overlays = []string{"a.json", "b.json"}
+			c.Players = 5
+		}},
+		{text: `expansions = []`, want: func(c *Config) { c.Expansions = nil }},
+		{text: "[storage]\nbackend = \"memory\"", want: func(c *Config) { c.Storage.Backend = "memory" }},
+		{text: "# just a comment\n\n", want: func(c *Config) {}},
+		{text: `colour = "blue"`, err: `unknown key "colour"`},
+		{text: "[storage]\nplayers = 4", err: `unknown key "players"`},
+		{text: "[players]", err: "unknown table"},
+		{text: "[storage", err: "bad table header"},
+		{text: "players", err: "expected key = value"},
+		{text: `players = "four"`, err: "expected an integer"},
+		{text: `players = 4.5`, err: "expected an integer"},
+		{text: `data = cards.json`, err: "expected a quoted string"},
+		{text: `data = "cards.json`, err: "expected a quoted string"},
+		{text: `data = "bad \q"`, err: "bad string"},
+		{text: `expansions = "baseset"`, err: "expected an array"},
+		{text: `expansions = [1, 2]`, err: "expected a quoted string"},
+	} {
+		c, want := Default(), Default()
+		err := decode(tc.text, c)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%q: error %v, want one saying %q", tc.text, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tc.text, err)
+			continue
+		}
+		tc.want(want)
+		if !reflect.DeepEqual(c, want) {
+			t.Errorf("%q: decoded %+v, want %+v", tc.text, c, want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	for _, tc := range []struct {
+		change func(c *Config)
+		err    string
+	}{
+		{func(c *Config) {}, ""},
+		{func(c *Config) { c.Players = 2 }, "Players must be 3 to 5"},
+		{func(c *Config) { c.Players = 6 }, "Players must be 3 to 5"},
+		{func(c *Config) { c.LossPercent = 0 }, "Loss_percent must be 1 to 99"},
+		{func(c *Config) { c.LossPercent = 100 }, "Loss_percent must be 1 to 99"},
+		{func(c *Config) { c.Expansions = []string{"bogus"} }, "bogus"},
+		{func(c *Config) { c.Storage.Backend = "sql" }, "needs a driver"},
+		{func(c *Config) { c.Storage.Backend, c.Storage.Driver = "sql", "sqlite" }, ""},
+		{func(c *Config) { c.Storage.Backend = "cloud" }, "Unknown storage backend"},
+	} {
+		c := Default()
+		tc.change(c)
+		err := c.check()
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("%+v: %v", c, err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("%+v: error %v, want one saying %q", c, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// decode reads the subset of TOML the configuration needs into the struct
+// pointed to by v: comments, [table] headers one level deep, and key =
+// value pairs whose values are basic strings, integers, booleans or arrays
+// of strings.  Keys are matched to fields by their toml tags; unknown keys
+// are errors.
+func decode(text string, v interface{}) error {
+	table := reflect.ValueOf(v).Elem()
+	top := table
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		n := i + 1
+		line := strings.TrimSpace(stripComment(lines[i]))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return fmt.Errorf("Line %d: bad table header.", n)
+			}
+			f, ok := field(top, strings.TrimSpace(line[1:len(line)-1]))
+			if !ok || f.Kind() != reflect.Struct {
+				return fmt.Errorf("Line %d: unknown table %s.", n, line)
+			}
+			table = f
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("Line %d: expected key = value.", n)
+		}
+		key, val := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		// arrays may continue over several lines.
+		for strings.HasPrefix(val, "[") && !strings.HasSuffix(val, "]") && i+1 < len(lines) {
+			i++
+			val += " " + strings.TrimSpace(stripComment(lines[i]))
+		}
+		f, ok := field(table, key)
+		if !ok {
+			return fmt.Errorf("Line %d: unknown key %q.", n, key)
+		}
+		if err := setValue(f, val); err != nil {
+			return fmt.Errorf("Line %d: %s: %v", n, key, err)
+		}
+	}
+	return nil
+}
+
+// field returns the field of the struct s tagged with the given key.
+func field(s reflect.Value, key string) (reflect.Value, bool) {
+	t := s.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("toml") == key {
+			return s.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// setValue parses val into f according to f's kind.
+func setValue(f reflect.Value, val string) error {
+	switch f.Kind() {
+	case reflect.String:
+		s, err := parseString(val)
+		if err != nil {
+			return err
+		}
+		f.SetString(s)
+	case reflect.Int:
+		i, err := strconv.Atoi(strings.Replace(val, "_", "", -1))
+		if err != nil {
+			return fmt.Errorf("expected an integer, not %s", val)
+		}
+		f.SetInt(int64(i))
+	case reflect.Bool:
+		switch val {
+		case "true":
+			f.SetBool(true)
+		case "false":
+			f.SetBool(false)
+		default:
+			return fmt.Errorf("expected true or false, not %s", val)
+		}
+	case reflect.Slice:
+		if !strings.HasPrefix(val, "[") || !strings.HasSuffix(val, "]") {
+			return fmt.Errorf("expected an array, not %s", val)
+		}
+		var list []string
+		for _, item := range splitArray(val[1 : len(val)-1]) {
+			s, err := parseString(item)
+			if err != nil {
+				return err
+			}
+			list = append(list, s)
+		}
+		f.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("can't set a %s", f.Kind())
+	}
+	return nil
+}
+
+// parseString parses a basic or literal TOML string.
+func parseString(val string) (string, error) {
+	if len(val) >= 2 && val[0] == '\'' && val[len(val)-1] == '\'' {
+		return val[1 : len(val)-1], nil
+	}
+	if len(val) < 2 || val[0] != '"' || val[len(val)-1] != '"' {
+		return "", fmt.Errorf("expected a quoted string, not %s", val)
+	}
+	s, err := strconv.Unquote(val)
+	if err != nil {
+		return "", fmt.Errorf("bad string %s", val)
+	}
+	return s, nil
+}
+
+// splitArray splits the inside of an array at commas outside strings,
+// dropping empty items such as the one after a trailing comma.
+func splitArray(s string) []string {
+	var items []string
+	start, quote := 0, byte(0)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, s[start:i])
+			start = i + 1
+		}
+	}
+	items = append(items, s[start:])
+	var r []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			r = append(r, item)
+		}
+	}
+	return r
+}
+
+// stripComment removes a # comment that isn't inside a string.
+func stripComment(line string) string {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
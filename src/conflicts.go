@@ -0,0 +1,46 @@
+package sentinels
+
+import "fmt"
+
+// Conflict is a villain and environment that don't play well together, for
+// example because of a rules problem or a degenerate interaction.  Villain
+// may name a base villain, which covers its variants too.
+type Conflict struct {
+	Villain     string `json:"villain"`
+	Environment string `json:"environment"`
+	Reason      string `json:"reason"`
+}
+
+// Conflicts returns the known conflicts between the setup's villain and
+// environment.
+func (s *Setup) Conflicts() []Conflict {
+	var r []Conflict
+	for _, c := range loaded().sd.Conflicts {
+		if (c.Villain == s.Villain.Name || c.Villain == s.Villain.Base) && c.Environment == s.Environment.Name {
+			r = append(r, c)
+		}
+	}
+	return r
+}
+
+// checkConflicts returns an error if a conflict names an unknown villain or
+// environment.
+func checkConflicts(sd *SentinelsData) error {
+	names := func(ds []Difficulty) map[string]bool {
+		m := make(map[string]bool)
+		for _, d := range ds {
+			m[d.Name] = true
+		}
+		return m
+	}
+	villains, envs := names(sd.Difficulty.Villain), names(sd.Difficulty.Env)
+	for i, c := range sd.Conflicts {
+		if !villains[c.Villain] {
+			return fmt.Errorf("Conflict %d names an unknown villain %q.", i, c.Villain)
+		}
+		if !envs[c.Environment] {
+			return fmt.Errorf("Conflict %d names an unknown environment %q.", i, c.Environment)
+		}
+	}
+	return nil
+}
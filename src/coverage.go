@@ -0,0 +1,94 @@
+package sentinels
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// coverageCandidates is the number of setups SuggestCoverage considers for
+// each suggestion.
+const coverageCandidates = 50
+
+// Coverage reports how often each card in a collection has been played.
+type Coverage struct {
+	Uses   map[string]int // times each card was in a setup that wasn't vetoed
+	Unused *CardSet       // cards that have never been played
+}
+
+// Coverage counts the uses of each card in cs in the history.
+func (h *History) Coverage(cs *CardSet) *Coverage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	cv := &Coverage{Uses: make(map[string]int)}
+	for _, e := range h.Entries {
+		if e.Vetoed {
+			continue
+		}
+		for _, n := range e.Heroes {
+			cv.Uses[n]++
+		}
+		cv.Uses[e.Villain]++
+		cv.Uses[e.Environment]++
+	}
+	cv.Unused = cs.filter(func(c *Card) bool { return cv.Uses[c.Name] == 0 })
+	return cv
+}
+
+// String formats the unused cards.
+func (cv *Coverage) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Never played:\n%s", cv.Unused)
+	return b.String()
+}
+
+// SuggestCoverage suggests up to n setups at p's target that between them
+// use as many never-played cards from p's collection as possible.  The
+// suggestions are not recorded in the history.
+func (h *History) SuggestCoverage(p *Params, n int) ([]*Setup, error) {
+	unused := make(map[string]bool)
+	for _, c := range h.Coverage(p.CardSet()).Unused.names() {
+		unused[c] = true
+	}
+	var r []*Setup
+	for len(r) < n && len(unused) > 0 {
+		var best *Setup
+		bestN := 0
+		for i := 0; i < coverageCandidates; i++ {
+			q, err := newSearchFor(p)
+			if err != nil {
+				return nil, err
+			}
+			s, _, err := q.run(NewSeed())
+			if err != nil {
+				return nil, err
+			}
+			if k := s.count(unused); k > bestN {
+				best, bestN = s, k
+			}
+		}
+		if best == nil {
+			break
+		}
+		for _, c := range best.cards() {
+			delete(unused, c.Name)
+		}
+		r = append(r, best)
+	}
+	return r, nil
+}
+
+// cards returns all the cards in the setup.
+func (s *Setup) cards() []*Card {
+	return append(append([]*Card(nil), s.Heroes...), s.Villain, s.Environment)
+}
+
+// count returns the number of the setup's cards whose names are in set.
+func (s *Setup) count(set map[string]bool) int {
+	n := 0
+	for _, c := range s.cards() {
+		if set[c.Name] {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,217 @@
+package sentinels
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// LoadData replaces the card and scale data with data in the same form as
+// the built-in JSON, or with the built-in data if data is nil.  If custom
+// isn't nil, it is an overlay of custom cards applied to the data; see
+// LoadLayers.
+func LoadData(data, custom []byte) error {
+	if custom == nil {
+		return LoadLayers(data)
+	}
+	return LoadLayers(data, custom)
+}
+
+// LoadLayers replaces the card and scale data with data in the same form as
+// the built-in JSON, or with the built-in data if data is nil, and then
+// applies each overlay in turn.  An overlay holds cards in the form of the
+// "difficulty" field, which are added to the data, replacing cards whose
+// names have the same CardKey, and may list cards to leave out of card sets
+// under "hide", e.g.
+//
+//	{"hero": [{"name": "Legacy", "points": -40}], "hide": ["Baron Blade"]}
+//
+// Hidden cards can still be scored by name.  Cards not listed in
+// ExpansionCards count as base set cards.  Nothing changes unless the
+// result passes ValidateCards, has a sorted scale and points for each
+// number of heroes, and its conflicts name known cards; see RepairData.
+// It may be called while other goroutines generate setups; searches keep
+// the data they started with.
+func LoadLayers(data []byte, overlays ...[]byte) error {
+	if data == nil {
+		data = sdBytes
+	}
+	nsd, err := buildData(data, overlays...)
+	if err != nil {
+		return err
+	}
+	// the built-in data needn't be parsed once this has replaced it.
+	dataOnce.Do(func() {})
+	useData(nsd, data, overlays...)
+	return nil
+}
+
+// CheckLayers returns the error LoadLayers would for data and the
+// overlays, without loading them.
+func CheckLayers(data []byte, overlays ...[]byte) error {
+	if data == nil {
+		data = sdBytes
+	}
+	_, err := buildData(data, overlays...)
+	return err
+}
+
+// overlay is a layer of custom cards; see LoadLayers.
+type overlay struct {
+	DifficultyData
+	Hide []string `json:",omitempty"`
+}
+
+// buildData parses data, applies the overlays to it and checks the result
+// as LoadLayers describes.
+func buildData(data []byte, overlays ...[]byte) (*SentinelsData, error) {
+	nsd := &SentinelsData{}
+	if err := json.Unmarshal(data, nsd); err != nil {
+		return nil, err
+	}
+	for i, b := range overlays {
+		ov := &overlay{}
+		if err := json.Unmarshal(b, ov); err != nil {
+			return nil, fmt.Errorf("Overlay %d: %v", i+1, err)
+		}
+		nsd.Difficulty.Hero = mergeCards(nsd.Difficulty.Hero, ov.Hero)
+		nsd.Difficulty.Villain = mergeCards(nsd.Difficulty.Villain, ov.Villain)
+		nsd.Difficulty.Env = mergeCards(nsd.Difficulty.Env, ov.Env)
+		for _, n := range ov.Hide {
+			if !hideCard(&nsd.Difficulty, n) {
+				return nil, fmt.Errorf("Overlay %d hides unknown card %q.", i+1, n)
+			}
+		}
+	}
+	for _, d := range ValidateCards(&nsd.Difficulty) {
+		if d.Severity == "error" {
+			return nil, fmt.Errorf("Invalid card data: %s", d)
+		}
+	}
+	if len(nsd.Scale) < 2 {
+		return nil, fmt.Errorf("The data has %d scale points; at least 2 are needed.", len(nsd.Scale))
+	}
+	if err := checkScale(nsd.Scale); err != nil {
+		return nil, err
+	}
+	if len(nsd.Difficulty.Nump) < 3 {
+		return nil, fmt.Errorf("The data has points for %d numbers of heroes; 3 are needed.", len(nsd.Difficulty.Nump))
+	}
+	for n, sc := range nsd.Scales {
+		if n < 3 || n > len(nsd.Difficulty.Nump)+2 {
+			return nil, fmt.Errorf("The data has a scale for %d heroes; a game has 3 to %d.", n, len(nsd.Difficulty.Nump)+2)
+		}
+		if len(sc) < 2 {
+			return nil, fmt.Errorf("The scale for %d heroes has %d points; at least 2 are needed.", n, len(sc))
+		}
+		if err := checkScale(sc); err != nil {
+			return nil, fmt.Errorf("For %d heroes: %v", n, err)
+		}
+	}
+	if err := checkConflicts(nsd); err != nil {
+		return nil, err
+	}
+	return nsd, nil
+}
+
+// hideCard marks the named card hidden, reporting whether there is one.
+// Names match as CardKey compares them.
+func hideCard(dd *DifficultyData, name string) bool {
+	key := CardKey(name)
+	for _, l := range [][]Difficulty{dd.Hero, dd.Villain, dd.Env} {
+		for i := range l {
+			if CardKey(l[i].Name) == key {
+				l[i].Hidden = true
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergeCards adds the cards in add to list, replacing those with the same
+// CardKey.  A replaced card keeps the name it had in list.
+func mergeCards(list, add []Difficulty) []Difficulty {
+	r := append([]Difficulty(nil), list...)
+	index := make(map[string]int)
+	for i, d := range r {
+		index[CardKey(d.Name)] = i
+	}
+	for _, d := range add {
+		key := CardKey(d.Name)
+		if i, ok := index[key]; ok {
+			d.Name = r[i].Name
+			r[i] = d
+		} else {
+			index[key] = len(r)
+			r = append(r, d)
+		}
+	}
+	return r
+}
+
+// checkScale returns an error if the scale's totals don't fall or its loss
+// percentages rise from one entry to the next.
+func checkScale(sc []ScaleData) error {
+	for i := 1; i < len(sc); i++ {
+		if sc[i].Total >= sc[i-1].Total || sc[i].LossPct > sc[i-1].LossPct {
+			return fmt.Errorf("The scale is out of order at entry %d (total %d, %d%%); repair it with RepairData.", i, sc[i].Total, sc[i].LossPct)
+		}
+	}
+	return nil
+}
+
+// RepairScale returns a copy of the scale sorted by total, highest first,
+// with entries for the same total merged into one with their mean loss
+// percentage, and with each loss percentage lowered if need be to no more
+// than the one before it.
+func RepairScale(sc []ScaleData) []ScaleData {
+	sorted := append([]ScaleData(nil), sc...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Total > sorted[j].Total })
+	var r []ScaleData
+	for i := 0; i < len(sorted); {
+		j, sum := i, 0
+		for ; j < len(sorted) && sorted[j].Total == sorted[i].Total; j++ {
+			sum += sorted[j].LossPct
+		}
+		v := ScaleData{sorted[i].Total, int(math.Floor(float64(sum)/float64(j-i) + 0.5))}
+		if len(r) > 0 && v.LossPct > r[len(r)-1].LossPct {
+			v.LossPct = r[len(r)-1].LossPct
+		}
+		r = append(r, v)
+		i = j
+	}
+	return r
+}
+
+// RepairData returns data, which is in the form of the built-in JSON, with
+// its scales repaired by RepairScale.  The rest of the data is unchanged.
+func RepairData(data []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	var sc []ScaleData
+	if err := json.Unmarshal(fields["scale"], &sc); err != nil {
+		return nil, fmt.Errorf("Couldn't read the scale: %v", err)
+	}
+	b, err := json.Marshal(RepairScale(sc))
+	if err != nil {
+		return nil, err
+	}
+	fields["scale"] = b
+	if raw, ok := fields["scales"]; ok {
+		var scs map[int][]ScaleData
+		if err := json.Unmarshal(raw, &scs); err != nil {
+			return nil, fmt.Errorf("Couldn't read the scales: %v", err)
+		}
+		for n, sc := range scs {
+			scs[n] = RepairScale(sc)
+		}
+		if fields["scales"], err = json.Marshal(scs); err != nil {
+			return nil, err
+		}
+	}
+	return json.MarshalIndent(fields, "", "\t")
+}
@@ -0,0 +1,134 @@
+package sentinels
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// describeMax is the number of reasons Describe gives for a setup's
+// difficulty.
+const describeMax = 2
+
+// factor is one part of a setup's difficulty.
+type factor struct {
+	what   string
+	points int
+	kind   string // "villain", "environment" or "" for others
+}
+
+func (f factor) String() string {
+	return fmt.Sprintf("%s (%s)", f.what, signed(f.points))
+}
+
+// signed formats points with an explicit sign, using a true minus sign.
+func signed(n int) string {
+	if n < 0 {
+		return fmt.Sprintf("−%d", -n)
+	}
+	return fmt.Sprintf("+%d", n)
+}
+
+// lossWord describes an expected loss percentage.
+func lossWord(lp int) string {
+	switch {
+	case lp < 20:
+		return "a very easy"
+	case lp < 40:
+		return "an easy"
+	case lp < 60:
+		return "an even"
+	case lp < 80:
+		return "a hard"
+	}
+	return "a brutal"
+}
+
+// Describe explains the setup's difficulty in a sentence, e.g. "This is a
+// hard game (est. 72% loss) mainly because Iron Legacy (+70) in Rook City
+// (+74) outweighs your strong hero lineup (−101)."  It then warns of
+// villain phases that will make the game much harder or easier; see Spikes.
+func (s *Setup) Describe() string {
+	d := s.explain()
+	if a := s.phaseAdvice(); a != "" {
+		d += " " + a
+	}
+	return d
+}
+
+// explain gives the reasons for the setup's difficulty, for Describe.
+func (s *Setup) explain() string {
+	heroes := 0
+	for _, h := range s.Heroes {
+		heroes += s.HeroPoints(h)
+	}
+	lineup := "your weak hero lineup"
+	if heroes < 0 {
+		lineup = "your strong hero lineup"
+	}
+	all := []factor{
+		{s.villainName(), s.VillainPoints(), "villain"},
+		{s.Environment.DisplayName(s.Lang), s.Environment.Points, "environment"},
+		{lineup, heroes, ""},
+		{fmt.Sprintf("playing with %d heroes", len(s.Heroes)), s.PcPoints, ""},
+	}
+	harder := s.LossPercent >= 50
+	var main, other []factor
+	for _, f := range all {
+		switch {
+		case f.points == 0:
+		case (f.points > 0) == harder:
+			main = append(main, f)
+		default:
+			other = append(other, f)
+		}
+	}
+	sort.SliceStable(main, func(i, j int) bool { return abs(main[i].points) > abs(main[j].points) })
+	sort.SliceStable(other, func(i, j int) bool { return abs(other[i].points) > abs(other[j].points) })
+	if len(main) > describeMax {
+		main = main[:describeMax]
+	}
+	if len(other) > describeMax {
+		other = other[:describeMax]
+	}
+	head := fmt.Sprintf("This is %s game (est. %s loss)", lossWord(s.LossPercent), FormatPercent(s.Lang, s.LossPercent))
+	if len(main) == 0 {
+		return head + "."
+	}
+	because, plural := joinFactors(main)
+	if len(other) == 0 {
+		return fmt.Sprintf("%s mainly because of %s.", head, because)
+	}
+	verb := "outweighs"
+	if plural {
+		verb = "outweigh"
+	}
+	against, _ := joinFactors(other)
+	return fmt.Sprintf("%s mainly because %s %s %s.", head, because, verb, against)
+}
+
+// joinFactors lists factors in prose, reporting whether the result is
+// plural.  A villain in an environment reads as one thing.
+func joinFactors(fs []factor) (string, bool) {
+	if len(fs) == 2 {
+		v, e := fs[0], fs[1]
+		if v.kind == "environment" {
+			v, e = e, v
+		}
+		if v.kind == "villain" && e.kind == "environment" {
+			return fmt.Sprintf("%s in %s", v, e), false
+		}
+	}
+	parts := make([]string, len(fs))
+	for i, f := range fs {
+		parts[i] = f.String()
+	}
+	return strings.Join(parts, " and "), len(fs) > 1
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,143 @@
+package sentinels
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DigestDays is the number of days a digest covers.
+const DigestDays = 7
+
+// digestAchievements is the number of unearned achievements a digest
+// suggests working toward.
+const digestAchievements = 2
+
+// Digest summarizes a period of a group's games for its organizer.
+type Digest struct {
+	Since, Until time.Time
+	Setups       int // setups generated, not counting vetoed ones
+	Vetoed       int
+	Games, Wins  int // results recorded
+	// Predicted is the mean expected loss percentage of the games with
+	// results and Actual the percentage lost.  Drift is their difference,
+	// positive if the games were harder than predicted, and AllTimeDrift
+	// the same over the whole history.
+	Predicted, Actual   float64
+	Drift, AllTimeDrift float64
+	// Challenges are setups suggested to play next: ones using cards never
+	// played, or else one a step harder than the group's target.
+	Challenges []*Setup
+	// Achievements are those the group is closest to earning.
+	Achievements []*Achievement
+}
+
+// Digest summarizes the games since the given time, suggesting challenges
+// from p's collection at its target.  The suggestions are not recorded in
+// the history.
+func (h *History) Digest(since time.Time, p *Params) (*Digest, error) {
+	d := &Digest{Since: since, Until: Now()}
+	h.mu.Lock()
+	var allLp, allLost float64
+	allGames := 0
+	for _, e := range h.Entries {
+		lost := 0.0
+		if e.Result == Lost {
+			lost = 100
+		}
+		if e.Result != "" {
+			allGames++
+			allLp += float64(e.LossPercent)
+			allLost += lost
+		}
+		if e.Time.Before(since) {
+			continue
+		}
+		if e.Vetoed {
+			d.Vetoed++
+			continue
+		}
+		d.Setups++
+		if e.Result != "" {
+			d.Games++
+			d.Predicted += float64(e.LossPercent)
+			d.Actual += lost
+			if e.Result == Won {
+				d.Wins++
+			}
+		}
+	}
+	h.mu.Unlock()
+	if d.Games > 0 {
+		d.Predicted /= float64(d.Games)
+		d.Actual /= float64(d.Games)
+		d.Drift = d.Actual - d.Predicted
+	}
+	if allGames > 0 {
+		d.AllTimeDrift = (allLost - allLp) / float64(allGames)
+	}
+
+	var err error
+	if d.Challenges, err = h.SuggestCoverage(p, 2); err != nil {
+		return nil, err
+	}
+	if len(d.Challenges) == 0 {
+		q := *p
+		q.ByTotal = false
+		if q.LossPercent += 10; q.LossPercent > 99 {
+			q.LossPercent = 99
+		}
+		qs, err := newSearchFor(&q)
+		if err != nil {
+			return nil, err
+		}
+		s, _, err := qs.run(NewSeed())
+		if err != nil {
+			return nil, err
+		}
+		d.Challenges = []*Setup{s}
+	}
+
+	for _, a := range h.Achievements(p.CardSet()) {
+		if !a.Done() && a.Progress > 0 {
+			d.Achievements = append(d.Achievements, a)
+		}
+	}
+	sort.SliceStable(d.Achievements, func(i, j int) bool {
+		a, b := d.Achievements[i], d.Achievements[j]
+		return float64(a.Progress)/float64(a.Goal) > float64(b.Progress)/float64(b.Goal)
+	})
+	if len(d.Achievements) > digestAchievements {
+		d.Achievements = d.Achievements[:digestAchievements]
+	}
+	return d, nil
+}
+
+// String formats the digest as the text of an email.
+func (d *Digest) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Sentinels digest, %s to %s\n\n", d.Since.Format("2 January"), d.Until.Format("2 January 2006"))
+	fmt.Fprintf(&b, "%d setups generated (%d vetoed), %d results recorded", d.Setups, d.Vetoed, d.Games)
+	if d.Games > 0 {
+		fmt.Fprintf(&b, ": won %d, lost %d", d.Wins, d.Games-d.Wins)
+	}
+	fmt.Fprintln(&b, ".")
+	if d.Games > 0 {
+		fmt.Fprintf(&b, "\nThe games were predicted to lose %.0f%% of the time and lost %.0f%%, a drift of %+.0f points (%+.0f over all games).\n",
+			d.Predicted, d.Actual, d.Drift, d.AllTimeDrift)
+	}
+	if len(d.Challenges) > 0 {
+		fmt.Fprintln(&b, "\nSuggested next challenges:")
+		for i, s := range d.Challenges {
+			fmt.Fprintf(&b, "%d. %s\n   %s\n", i+1, s, s.Describe())
+		}
+	}
+	if len(d.Achievements) > 0 {
+		fmt.Fprintln(&b, "\nClosest achievements:")
+		for _, a := range d.Achievements {
+			fmt.Fprintf(&b, "- %s: %s (%d of %d)\n", a.Name, a.Description, a.Progress, a.Goal)
+		}
+	}
+	return b.String()
+}
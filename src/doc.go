@@ -0,0 +1,15 @@
+// Package sentinels generates and scores Sentinels of the Multiverse setups.
+// It is the engine behind the command-line tool and the web app, and has no
+// side effects on import: the card data is parsed by EnsureData when first
+// needed, and nothing is registered or listened on.  Importing it brings in
+// none of the module's other packages.
+//
+// Storage of profiles and history goes through the Store interface.
+// FileStore, which keeps them in JSON files, is provided here; package
+// storage provides MemoryStore and SQLStore.
+//
+// The rest of the module builds on the engine: httpserver is the web app,
+// bots/webhook and bots/scheduler post setups elsewhere, config reads the
+// settings file, and cmd/sentinels and cmd/sentinels-server are the
+// command-line tool and the standalone server.
+package sentinels
@@ -0,0 +1,58 @@
+package sentinels
+
+import (
+	"errors"
+)
+
+// Draft is a set of hero offers, one per player.  Each player picks one
+// hero from their offer; no base hero appears in more than one offer, so
+// any combination of picks is a legal team.
+type Draft struct {
+	Offers [][]*Card
+}
+
+// DealDraft deals each of p.Players players a choice of k heroes from the
+// card set selected by p.
+func DealDraft(p *Params, k int) (*Draft, error) {
+	if k < 1 {
+		return nil, errors.New("Each player must be offered at least one hero.")
+	}
+	cs := p.CardSet()
+	byBase := make(map[string][]*Card)
+	var bases []string
+	for _, c := range cs.Heroes {
+		if byBase[c.Base] == nil {
+			bases = append(bases, c.Base)
+		}
+		byBase[c.Base] = append(byBase[c.Base], c)
+	}
+	n := p.Players * k
+	if n > len(bases) {
+		return nil, errors.New("Not enough different heroes for this draft.")
+	}
+	d := &Draft{Offers: make([][]*Card, p.Players)}
+	picked, err := pick(randIntn, len(bases), n)
+	if err != nil {
+		return nil, err
+	}
+	for i, b := range picked {
+		variants := byBase[bases[b]]
+		c := variants[randIntn(len(variants))]
+		d.Offers[i/k] = append(d.Offers[i/k], c)
+	}
+	return d, nil
+}
+
+// FinishDraft finds a villain and environment that bring the drafted heroes
+// to the difficulty targeted by p.
+func FinishDraft(p *Params, picks []*Card) (*Setup, int, error) {
+	if len(picks) != p.Players {
+		return nil, 0, errors.New("Every player must pick a hero.")
+	}
+	q := *p
+	q.Heroes = nil
+	for _, c := range picks {
+		q.Heroes = append(q.Heroes, c.Name)
+	}
+	return Find(&q)
+}
@@ -0,0 +1,105 @@
+package sentinels
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// CardValues are the points of a card that can be edited.
+type CardValues struct {
+	Points   int
+	Advanced int // for villains; 0 means no advanced-mode data
+}
+
+// CardEdit is how a custom overlay changes a card from the base data.
+type CardEdit struct {
+	Name string
+	Type string // "hero", "villain" or "env"
+	Old  CardValues
+	New  CardValues
+	// Added is set for a card the base data doesn't have, and Other for
+	// one the overlay changes in ways besides its points.
+	Added, Other bool
+}
+
+// EditOverlay sets the points of cards in a custom overlay, the form of
+// overlay LoadLayers takes, and returns the new overlay and how it differs
+// from the base data.  The base data is data, nil meaning the built-in
+// data, with the overlays in layers applied, and custom is the current
+// overlay on top of them, which may be nil.  Cards edited back to their
+// values in the base data are dropped from the overlay.  The result must
+// pass LoadLayers' checks.
+func EditOverlay(data []byte, layers [][]byte, custom []byte, edits map[string]CardValues) ([]byte, []CardEdit, error) {
+	if data == nil {
+		data = sdBytes
+	}
+	base, err := buildData(data, layers...)
+	if err != nil {
+		return nil, nil, err
+	}
+	ov := &overlay{}
+	if custom != nil {
+		if err := json.Unmarshal(custom, ov); err != nil {
+			return nil, nil, err
+		}
+	}
+	lists := []struct {
+		typ     string
+		base    []Difficulty
+		overlay *[]Difficulty
+	}{
+		{"hero", base.Difficulty.Hero, &ov.Hero},
+		{"villain", base.Difficulty.Villain, &ov.Villain},
+		{"env", base.Difficulty.Env, &ov.Env},
+	}
+	found := make(map[string]bool)
+	var changes []CardEdit
+	for _, l := range lists {
+		inBase := make(map[string]Difficulty)
+		for _, d := range l.base {
+			inBase[d.Name] = d
+		}
+		inOverlay := make(map[string]bool)
+		for _, d := range *l.overlay {
+			inOverlay[d.Name] = true
+		}
+		for _, d := range l.base {
+			if _, ok := edits[d.Name]; ok && !inOverlay[d.Name] {
+				*l.overlay = append(*l.overlay, d)
+			}
+		}
+		var kept []Difficulty
+		for _, d := range *l.overlay {
+			if v, ok := edits[d.Name]; ok {
+				d.Points, d.Advanced = v.Points, v.Advanced
+				found[d.Name] = true
+			}
+			b, ok := inBase[d.Name]
+			if ok && reflect.DeepEqual(b, d) {
+				continue
+			}
+			kept = append(kept, d)
+			c := CardEdit{Name: d.Name, Type: l.typ, Old: CardValues{b.Points, b.Advanced}, New: CardValues{d.Points, d.Advanced}, Added: !ok}
+			b.Points, b.Advanced = d.Points, d.Advanced
+			c.Other = ok && !reflect.DeepEqual(b, d)
+			changes = append(changes, c)
+		}
+		*l.overlay = kept
+	}
+	for n := range edits {
+		if !found[n] {
+			return nil, nil, fmt.Errorf("Unknown card %q.", n)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	b, err := json.MarshalIndent(ov, "", "\t")
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := buildData(data, append(layers[:len(layers):len(layers)], b)...); err != nil {
+		return nil, nil, err
+	}
+	return b, changes, nil
+}
@@ -0,0 +1,41 @@
+package sentinels
+
+import "sync"
+
+// Kinds of Event.
+const (
+	Generated = "generated" // a setup was generated, including by a veto
+	Played    = "played"    // a result was recorded for a setup
+)
+
+// Event reports something that happened to a setup.  Setup is nil for a
+// played setup whose cards are no longer in the data.
+type Event struct {
+	Kind  string
+	Setup *Setup
+	Entry *HistoryEntry
+}
+
+var (
+	listenMu  sync.Mutex
+	listeners []func(Event)
+)
+
+// Listen adds a function to be called after every event.  Listeners are
+// called in turn on the goroutine that caused the event, so slow ones
+// should do their work elsewhere.
+func Listen(f func(Event)) {
+	listenMu.Lock()
+	defer listenMu.Unlock()
+	listeners = append(listeners, f)
+}
+
+// notify calls the listeners.
+func notify(e Event) {
+	listenMu.Lock()
+	ls := listeners
+	listenMu.Unlock()
+	for _, f := range ls {
+		f(e)
+	}
+}
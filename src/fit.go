@@ -0,0 +1,157 @@
+package sentinels
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultPrior is how far, in points, a card's published points are
+// expected to be from its true difficulty for a group.  Smaller priors need
+// more games to move a card.
+const DefaultPrior = 30
+
+// fitRounds is the number of passes Fit makes over the cards.
+const fitRounds = 50
+
+// CardFit is one card's points before and after fitting them to a group's
+// results.
+type CardFit struct {
+	Name   string
+	Points int // published points
+	Fitted int // points estimated from the group's games
+	Games  int // recorded games the card was in
+	Wins   int
+}
+
+// Fit is a personalized card table: each card's points updated from a
+// history's recorded wins and losses.
+type Fit struct {
+	Games  int        // recorded games fitted
+	Cards  []*CardFit // largest change first
+	adjust map[string]int
+}
+
+// Fit treats each card's points as a prior, normally distributed about the
+// published points with standard deviation prior, and finds the points
+// that best explain the history's results.  A game's loss chance is the
+// scale's loss percentage at its difficulty, approximated by a logistic
+// curve, so each game moves its cards' points by an amount depending on how
+// surprising its result was.  Vetoed and unplayed setups are ignored.
+func (h *History) Fit(prior float64) *Fit {
+	h.mu.Lock()
+	var games []*HistoryEntry
+	for _, e := range h.Entries {
+		if e.Result != "" && !e.Vetoed {
+			games = append(games, e)
+		}
+	}
+	h.mu.Unlock()
+	f := &Fit{Games: len(games), adjust: make(map[string]int)}
+	if EnsureData() != nil || prior <= 0 {
+		return f
+	}
+	a, b := loaded().sd.logistic()
+	delta := make(map[string]float64)
+	byName := make(map[string]*CardFit)
+	names := func(e *HistoryEntry) []string {
+		return append(append([]string(nil), e.Heroes...), e.Villain, e.Environment)
+	}
+	for _, e := range games {
+		for _, n := range names(e) {
+			cf := byName[n]
+			if cf == nil {
+				cf = &CardFit{Name: n}
+				if c, ok := Lookup(n); ok {
+					cf.Points = c.Points
+				}
+				byName[n] = cf
+				f.Cards = append(f.Cards, cf)
+			}
+			cf.Games++
+			if e.Result == Won {
+				cf.Wins++
+			}
+		}
+	}
+	// Newton steps on one card at a time, holding the others fixed.
+	for round := 0; round < fitRounds; round++ {
+		for _, cf := range f.Cards {
+			grad, hess := -delta[cf.Name]/(prior*prior), 1/(prior*prior)
+			for _, e := range games {
+				ns := names(e)
+				in := false
+				d := float64(e.Difficulty)
+				for _, n := range ns {
+					in = in || n == cf.Name
+					d += delta[n]
+				}
+				if !in {
+					continue
+				}
+				p := 1 / (1 + math.Exp(-(a + b*d)))
+				y := 0.0
+				if e.Result == Lost {
+					y = 1
+				}
+				grad += b * (y - p)
+				hess += b * b * p * (1 - p)
+			}
+			delta[cf.Name] += grad / hess
+		}
+	}
+	for _, cf := range f.Cards {
+		adj := int(math.Floor(delta[cf.Name] + 0.5))
+		cf.Fitted = cf.Points + adj
+		if adj != 0 {
+			f.adjust[cf.Name] = adj
+		}
+	}
+	sort.SliceStable(f.Cards, func(i, j int) bool {
+		return abs(f.Cards[i].Fitted-f.Cards[i].Points) > abs(f.Cards[j].Fitted-f.Cards[j].Points)
+	})
+	return f
+}
+
+// logistic fits the scale with a logistic curve: the loss chance at
+// difficulty d is about 1/(1+exp(-(a+b*d))).
+func (sd *SentinelsData) logistic() (a, b float64) {
+	var n, sx, sy, sxx, sxy float64
+	for _, v := range sd.scale() {
+		p := math.Min(math.Max(float64(v.LossPct)/100, 0.01), 0.99)
+		x, y := float64(v.Total), math.Log(p/(1-p))
+		n++
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+	}
+	b = (n*sxy - sx*sy) / (n*sxx - sx*sx)
+	a = (sy - b*sx) / n
+	return a, b
+}
+
+// Model returns a difficulty model that scores setups with the fitted
+// points, so that generation can use them: assign it to Model.
+func (f *Fit) Model() DifficultyModel {
+	return FittedModel{Base: Model, Adjust: f.adjust}
+}
+
+// FittedModel scores setups as Base does, plus per-card adjustments.
+type FittedModel struct {
+	Base   DifficultyModel
+	Adjust map[string]int // points to add for each card, by name
+}
+
+// Score implements DifficultyModel.
+func (m FittedModel) Score(s *Setup) int {
+	t := m.Base.Score(s) + m.Adjust[s.Villain.Name] + m.Adjust[s.Environment.Name]
+	for _, h := range s.Heroes {
+		t += m.Adjust[h.Name]
+	}
+	return t
+}
+
+// WinProbability implements DifficultyModel.
+func (m FittedModel) WinProbability(s *Setup) float64 {
+	return 1 - float64(LossPercentFor(m.Score(s), len(s.Heroes)))/100
+}
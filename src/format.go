@@ -0,0 +1,111 @@
+package sentinels
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Table formats the setup as a column-aligned table, drawn with box-drawing
+// characters if box is set and plain ASCII otherwise.
+func (s *Setup) Table(box bool) string {
+	rows := [][]string{}
+	for _, h := range s.Heroes {
+		rows = append(rows, []string{"Hero", h.DisplayName(s.Lang), strconv.Itoa(s.HeroPoints(h))})
+	}
+	rows = append(rows,
+		[]string{"Villain", s.villainName(), strconv.Itoa(s.VillainPoints())},
+		[]string{"Environment", s.Environment.DisplayName(s.Lang), strconv.Itoa(s.Environment.Points)},
+		[]string{"Heroes", strconv.Itoa(len(s.Heroes)), strconv.Itoa(s.PcPoints)},
+		nil,
+		[]string{"Total", FormatPercent(s.Lang, s.LossPercent) + " expected loss", strconv.Itoa(s.Difficulty)})
+	return formatTable([]string{"", "Card", "Points"}, rows, []bool{false, false, true}, box)
+}
+
+// Table formats the card set as a column-aligned table, with names in the
+// given language; see Setup.Table.
+func (cs *CardSet) Table(lang string, box bool) string {
+	rows := [][]string{}
+	for _, l := range []struct {
+		typ   string
+		cards []*Card
+	}{{"Hero", cs.Heroes}, {"Villain", cs.Villains}, {"Environment", cs.Environments}} {
+		if len(l.cards) == 0 {
+			continue
+		}
+		if len(rows) > 0 {
+			rows = append(rows, nil)
+		}
+		for _, c := range l.cards {
+			rows = append(rows, []string{l.typ, c.DisplayName(lang), strconv.Itoa(c.Points), c.Expansion.Title()})
+		}
+	}
+	return formatTable([]string{"", "Card", "Points", "Expansion"}, rows, []bool{false, false, true, false}, box)
+}
+
+// tableChars holds the characters a table is drawn with: horizontal and
+// vertical lines, then corners and junctions left to right for the top,
+// middle and bottom rules.
+type tableChars struct {
+	h, v                string
+	top, middle, bottom [3]string
+}
+
+var (
+	asciiTable = tableChars{"-", "|", [3]string{"+", "+", "+"}, [3]string{"+", "+", "+"}, [3]string{"+", "+", "+"}}
+	boxTable   = tableChars{"─", "│", [3]string{"┌", "┬", "┐"}, [3]string{"├", "┼", "┤"}, [3]string{"└", "┴", "┘"}}
+)
+
+// formatTable lays out a header and rows in aligned columns, right-aligning
+// the columns marked in right.  A nil row draws a rule.
+func formatTable(header []string, rows [][]string, right []bool, box bool) string {
+	ch := asciiTable
+	if box {
+		ch = boxTable
+	}
+	width := make([]int, len(header))
+	for _, r := range append([][]string{header}, rows...) {
+		for i, cell := range r {
+			if n := utf8.RuneCountInString(cell); n > width[i] {
+				width[i] = n
+			}
+		}
+	}
+	var b bytes.Buffer
+	rule := func(c [3]string) {
+		b.WriteString(c[0])
+		for i, w := range width {
+			if i > 0 {
+				b.WriteString(c[1])
+			}
+			b.WriteString(strings.Repeat(ch.h, w+2))
+		}
+		b.WriteString(c[2] + "\n")
+	}
+	line := func(r []string) {
+		b.WriteString(ch.v)
+		for i, w := range width {
+			pad := strings.Repeat(" ", w-utf8.RuneCountInString(r[i]))
+			if right[i] {
+				b.WriteString(" " + pad + r[i] + " ")
+			} else {
+				b.WriteString(" " + r[i] + pad + " ")
+			}
+			b.WriteString(ch.v)
+		}
+		b.WriteString("\n")
+	}
+	rule(ch.top)
+	line(header)
+	rule(ch.middle)
+	for _, r := range rows {
+		if r == nil {
+			rule(ch.middle)
+		} else {
+			line(r)
+		}
+	}
+	rule(ch.bottom)
+	return b.String()
+}
@@ -0,0 +1,110 @@
+package sentinels
+
+import (
+	"context"
+	"time"
+)
+
+// Option sets one of the parameters of Generate.
+type Option func(*Params)
+
+// Generate finds a setup with the given options.  Without options it is
+// for 3 heroes at a 50% loss percentage, within 10 points, drawn from the
+// base set and mini-expansions.  The search gives up with ctx's error if
+// ctx is done first.  Vetoes of the setup aren't bound by ctx.
+func Generate(ctx context.Context, opts ...Option) (*Setup, int, error) {
+	p := &Params{Players: 3, LossPercent: 50, Range: 10, Expansions: []ExpansionType{BaseSet, MiniExpansion}}
+	for _, o := range opts {
+		o(p)
+	}
+	return find(ctx, p)
+}
+
+// Select returns the cards a search with the given options draws from.
+func Select(opts ...Option) *CardSet {
+	p := &Params{Players: 3, LossPercent: 50, Range: 10, Expansions: []ExpansionType{BaseSet, MiniExpansion}}
+	for _, o := range opts {
+		o(p)
+	}
+	return p.CardSet()
+}
+
+// WithParams starts from a copy of p; options after it change the copy.
+func WithParams(p *Params) Option {
+	return func(q *Params) { *q = *p }
+}
+
+// WithPlayers sets the number of heroes.
+func WithPlayers(n int) Option {
+	return func(p *Params) { p.Players = n }
+}
+
+// WithLossPercent targets a loss percentage, within rg points.
+func WithLossPercent(lp, rg int) Option {
+	return func(p *Params) { p.ByTotal, p.LossPercent, p.Range = false, lp, rg }
+}
+
+// WithTotal targets a difficulty total, within tol points.
+func WithTotal(tt, tol int) Option {
+	return func(p *Params) { p.ByTotal, p.TargetTotal, p.Tolerance = true, tt, tol }
+}
+
+// WithExpansions draws the cards from the given expansions.
+func WithExpansions(exp ...ExpansionType) Option {
+	return func(p *Params) { p.Expansions = exp }
+}
+
+// WithExcluded leaves out the named cards.
+func WithExcluded(names ...string) Option {
+	return func(p *Params) { p.Exclude = append(p.Exclude[:len(p.Exclude):len(p.Exclude)], names...) }
+}
+
+// WithLockedHeroes puts the named heroes in the setup.
+func WithLockedHeroes(names ...string) Option {
+	return func(p *Params) { p.Heroes = append(p.Heroes[:len(p.Heroes):len(p.Heroes)], names...) }
+}
+
+// WithLockedVillain uses only the named villain.
+func WithLockedVillain(name string) Option {
+	return func(p *Params) { p.Villain = name }
+}
+
+// WithLockedEnvironment uses only the named environment.
+func WithLockedEnvironment(name string) Option {
+	return func(p *Params) { p.Environment = name }
+}
+
+// WithSeed seeds the search's random numbers, to reproduce a setup.
+func WithSeed(seed int64) Option {
+	return func(p *Params) { p.Seed = seed }
+}
+
+// WithAdvanced plays the villain in advanced mode.
+func WithAdvanced(adv bool) Option {
+	return func(p *Params) { p.Advanced = adv }
+}
+
+// WithNemesis prefers or avoids setups in which a hero faces their nemesis.
+func WithNemesis(m NemesisMode) Option {
+	return func(p *Params) { p.Nemesis = m }
+}
+
+// WithStyle says how the target is to be reached.
+func WithStyle(st Style) Option {
+	return func(p *Params) { p.Style = st }
+}
+
+// WithMaxIterations limits the number of setups the search tries.
+func WithMaxIterations(n int) Option {
+	return func(p *Params) { p.MaxIterations = n }
+}
+
+// WithMaxDuration limits how long the search runs.
+func WithMaxDuration(d time.Duration) Option {
+	return func(p *Params) { p.MaxDuration = d }
+}
+
+// WithProgress calls f as the search goes; see Params.Progress.
+func WithProgress(f func(Progress)) Option {
+	return func(p *Params) { p.Progress = f }
+}
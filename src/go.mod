@@ -0,0 +1,3 @@
+module github.com/uhhhclem/sentinels
+
+go 1.22
@@ -0,0 +1,103 @@
+package sentinels
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// goldenCounts are the numbers of heroes, villains and environments in each
+// expansion of the built-in data.  Update them along with the data.
+var goldenCounts = map[ExpansionType][3]int{
+	BaseSet:            {10, 4, 4},
+	MiniExpansion:      {2, 2, 2},
+	RookCity:           {2, 4, 2},
+	InfernalRelics:     {2, 4, 2},
+	ShatteredTimelines: {2, 4, 2},
+	Vengeance:          {5, 1, 2},
+	Promos:             {18, 4, 0},
+}
+
+// CheckGolden checks card and scale data against what generation relies on:
+// the checks of ValidateCards, every name in ExpansionCards naming a card,
+// points for each number of heroes, and a scale whose totals fall and whose
+// loss percentages never rise from one entry to the next.  If data is nil
+// the built-in data is checked, and the number of cards of each type in
+// each expansion must also match the known counts.  The data is sound if no
+// diagnostic has severity "error".
+func CheckGolden(data []byte) []Diagnostic {
+	builtin := data == nil
+	if builtin {
+		data = sdBytes
+	}
+	d := &SentinelsData{}
+	if err := json.Unmarshal(data, d); err != nil {
+		return []Diagnostic{{"error", "data", 0, "", err.Error()}}
+	}
+	ds := ValidateCards(&d.Difficulty)
+	names := make(map[string]bool)
+	for _, l := range [][]Difficulty{d.Difficulty.Hero, d.Difficulty.Villain, d.Difficulty.Env} {
+		for _, c := range l {
+			names[c.Name] = true
+		}
+	}
+	for e := range ExpansionNames {
+		for i, n := range ExpansionCards[ExpansionType(e)] {
+			if !names[n] {
+				ds = append(ds, Diagnostic{"error", "expansion", i, n, fmt.Sprintf("listed in %s but not in the data", ExpansionType(e))})
+			}
+		}
+	}
+	if len(d.Difficulty.Nump) != 3 {
+		ds = append(ds, Diagnostic{"error", "nump", 0, "", fmt.Sprintf("points are given for %d numbers of heroes, not 3", len(d.Difficulty.Nump))})
+	}
+	ds = append(ds, goldenScale(d.Scale, "")...)
+	var counts []int
+	for n := range d.Scales {
+		counts = append(counts, n)
+	}
+	sort.Ints(counts)
+	for _, n := range counts {
+		if n < 3 || n > len(d.Difficulty.Nump)+2 {
+			ds = append(ds, Diagnostic{"error", "scales", n, "", fmt.Sprintf("a scale is given for %d heroes", n)})
+		}
+		ds = append(ds, goldenScale(d.Scales[n], fmt.Sprintf("%d heroes, ", n))...)
+	}
+	if builtin {
+		cards := cardMap(d)
+		for e := range ExpansionNames {
+			var n [3]int
+			for _, c := range cards {
+				if c.Expansion == ExpansionType(e) {
+					n[c.Type]++
+				}
+			}
+			if want := goldenCounts[ExpansionType(e)]; n != want {
+				ds = append(ds, Diagnostic{"error", "expansion", e, ExpansionType(e).String(),
+					fmt.Sprintf("has %d heroes, %d villains and %d environments; expected %d, %d and %d", n[0], n[1], n[2], want[0], want[1], want[2])})
+			}
+		}
+	}
+	return ds
+}
+
+// goldenScale checks a scale's totals fall and its loss percentages never
+// rise, naming its entries with the given prefix.
+func goldenScale(sc []ScaleData, prefix string) []Diagnostic {
+	var ds []Diagnostic
+	if len(sc) < 2 {
+		ds = append(ds, Diagnostic{"error", "scale", 0, strings.TrimSuffix(prefix, ", "), fmt.Sprintf("the scale has %d entries; at least 2 are needed", len(sc))})
+	}
+	for i := 1; i < len(sc); i++ {
+		prev, v := sc[i-1], sc[i]
+		name := fmt.Sprintf("%stotal %d", prefix, v.Total)
+		if v.Total >= prev.Total {
+			ds = append(ds, Diagnostic{"error", "scale", i, name, fmt.Sprintf("total is not below the previous entry's %d", prev.Total)})
+		}
+		if v.LossPct > prev.LossPct {
+			ds = append(ds, Diagnostic{"error", "scale", i, name, fmt.Sprintf("loss percentage %d is above the previous entry's %d", v.LossPct, prev.LossPct)})
+		}
+	}
+	return ds
+}
@@ -0,0 +1,59 @@
+package sentinels
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// HeatMap is the expected difficulty of each villain against each
+// environment in a card set, for an average team drawn from its heroes.
+type HeatMap struct {
+	Players  int
+	Advanced bool
+	// HeroPoints is the points of an average team, including the player
+	// count modifier.
+	HeroPoints   int
+	Villains     []string // the rows, easiest first
+	Environments []string // the columns, easiest first
+	Totals       [][]int  // Totals[v][e] is villain v's total in environment e
+	Loss         [][]int  // the expected loss percentage of each total
+}
+
+// HeatMap returns the heat map of the card set's villains and environments
+// for a team of n heroes.
+func (cs *CardSet) HeatMap(n int, advanced bool) (*HeatMap, error) {
+	if err := EnsureData(); err != nil {
+		return nil, err
+	}
+	sd := loaded().sd
+	if n < 3 || n > len(sd.Difficulty.Nump)+2 {
+		return nil, fmt.Errorf("A setup needs 3 to %d heroes, not %d.", len(sd.Difficulty.Nump)+2, n)
+	}
+	if len(cs.Heroes) == 0 || len(cs.Villains) == 0 || len(cs.Environments) == 0 {
+		return nil, errors.New("The cards chosen need heroes, villains and environments.")
+	}
+	heroes := 0
+	for _, h := range cs.Heroes {
+		heroes += h.PointsFor(n)
+	}
+	hm := &HeatMap{Players: n, Advanced: advanced, HeroPoints: sd.Difficulty.Nump[n-3].Points + heroes*n/len(cs.Heroes)}
+	villainPoints := func(c *Card) int { return villainScore(c, advanced) }
+	villains := append([]*Card(nil), cs.Villains...)
+	sort.SliceStable(villains, func(i, j int) bool { return villainPoints(villains[i]) < villainPoints(villains[j]) })
+	envs := append([]*Card(nil), cs.Environments...)
+	sort.SliceStable(envs, func(i, j int) bool { return envs[i].Points < envs[j].Points })
+	for _, e := range envs {
+		hm.Environments = append(hm.Environments, e.Name)
+	}
+	for _, v := range villains {
+		hm.Villains = append(hm.Villains, v.Name)
+		var totals, loss []int
+		for _, e := range envs {
+			t := hm.HeroPoints + villainPoints(v) + e.Points
+			totals, loss = append(totals, t), append(loss, sd.LossPercentFor(t, n))
+		}
+		hm.Totals, hm.Loss = append(hm.Totals, totals), append(hm.Loss, loss)
+	}
+	return hm, nil
+}
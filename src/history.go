@@ -0,0 +1,217 @@
+package sentinels
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// HistoryEntry records a generated setup.
+type HistoryEntry struct {
+	Time        time.Time
+	Token       string
+	VetoOf      string `json:",omitempty"` // token of the setup this one replaced
+	Vetoed      bool   `json:",omitempty"`
+	Heroes      []string
+	Villain     string
+	Environment string
+	Advanced    bool `json:",omitempty"`
+	Difficulty  int
+	LossPercent int
+	Seed        *SeedRecord `json:",omitempty"`
+	Result      string      `json:",omitempty"` // "won" or "lost", once played
+	// Seats maps each hero to the player seated at it, if players were.
+	Seats map[string]string `json:",omitempty"`
+	// Group is the profile of the group the setup was made for, if any.
+	Group string `json:",omitempty"`
+	// Notes and HouseRules are what the players noted about the game.
+	Notes      string   `json:",omitempty"`
+	HouseRules []string `json:",omitempty"`
+}
+
+// Results that can be recorded for a played setup.
+const (
+	Won  = "won"
+	Lost = "lost"
+)
+
+// History is a log of generated setups.  If Store is set, the log is saved
+// there after every change.  If Limit is positive, only the Limit most
+// recent entries are kept.
+type History struct {
+	mu      sync.Mutex
+	Store   Store
+	Limit   int
+	Entries []*HistoryEntry
+	index   historyIndex // built by Search; nil until then
+}
+
+// DefaultLimit is the number of entries DefaultHistory keeps until it is
+// replaced by a stored history.
+const DefaultLimit = 1000
+
+// DefaultHistory records the setups generated by the package.
+var DefaultHistory = &History{Limit: DefaultLimit}
+
+// LoadHistory reads a history from a JSON file and saves later changes to
+// the same file.  A missing file yields an empty history.
+func LoadHistory(path string) (*History, error) {
+	return OpenHistory(&FileStore{HistoryPath: path})
+}
+
+// Add appends an entry to the history.
+func (h *History) Add(e *HistoryEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Entries = append(h.Entries, e)
+	if h.Limit > 0 && len(h.Entries) > h.Limit {
+		h.Entries = append([]*HistoryEntry(nil), h.Entries[len(h.Entries)-h.Limit:]...)
+		h.index = nil
+	}
+	h.index.add(len(h.Entries)-1, e)
+	if h.Store != nil {
+		if err := h.Store.AddHistory(e); err != nil {
+			log.Printf("Couldn't save history: %v", err)
+		}
+	}
+}
+
+// MarkVetoed flags the entry with the given token as vetoed.
+func (h *History) MarkVetoed(token string) {
+	h.setVetoed(token, true)
+}
+
+// ClearVetoed removes the vetoed flag from the entry with the given token.
+func (h *History) ClearVetoed(token string) {
+	h.setVetoed(token, false)
+}
+
+func (h *History) setVetoed(token string, v bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range h.Entries {
+		if e.Token == token {
+			e.Vetoed = v
+		}
+	}
+	h.save()
+}
+
+// RecordResult records whether the heroes won the setup with the given
+// token, and reports it to listeners as a Played event.
+func (h *History) RecordResult(token string, won bool) error {
+	e := h.setResult(token, won)
+	if e == nil {
+		return errors.New("No setup with that token in the history.")
+	}
+	s, err := Score(e.Heroes, e.Villain, e.Environment, e.Advanced)
+	if err != nil {
+		s = nil
+	}
+	notify(Event{Played, s, e})
+	return nil
+}
+
+// setResult records a result and returns a copy of the entry it was
+// recorded in, or nil if there is none.
+func (h *History) setResult(token string, won bool) *HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range h.Entries {
+		if e.Token == token {
+			e.Result = Lost
+			if won {
+				e.Result = Won
+			}
+			h.save()
+			c := *e
+			return &c
+		}
+	}
+	return nil
+}
+
+// Entry returns the entry with the given token, or nil if there is none.
+func (h *History) Entry(token string) *HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range h.Entries {
+		if e.Token == token {
+			return e
+		}
+	}
+	return nil
+}
+
+// Recent returns copies of the n most recent entries, newest first.
+func (h *History) Recent(n int) []*HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var r []*HistoryEntry
+	for i := len(h.Entries) - 1; i >= 0 && len(r) < n; i-- {
+		c := *h.Entries[i]
+		r = append(r, &c)
+	}
+	return r
+}
+
+// Chain returns the entries vetoed on the way to the setup with the given
+// token, oldest first, followed by that setup's own entry.
+func (h *History) Chain(token string) []*HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var chain []*HistoryEntry
+	for token != "" {
+		var found *HistoryEntry
+		for _, e := range h.Entries {
+			if e.Token == token {
+				found = e
+				break
+			}
+		}
+		if found == nil {
+			break
+		}
+		chain = append([]*HistoryEntry{found}, chain...)
+		token = found.VetoOf
+	}
+	return chain
+}
+
+// save writes the history to its store; the caller must hold h.mu.
+func (h *History) save() {
+	if h.Store == nil {
+		return
+	}
+	if err := h.Store.PutHistory(h.Entries); err != nil {
+		log.Printf("Couldn't save history: %v", err)
+	}
+}
+
+// historyEntry makes a history entry for a setup.
+func (s *Setup) historyEntry() *HistoryEntry {
+	e := &HistoryEntry{
+		Time:        Now(),
+		Token:       s.Token,
+		VetoOf:      s.VetoOf,
+		Villain:     s.Villain.Name,
+		Environment: s.Environment.Name,
+		Advanced:    s.Advanced,
+		Difficulty:  s.Difficulty,
+		LossPercent: s.LossPercent,
+		Seed:        s.Seed,
+		Notes:       s.Notes,
+		HouseRules:  s.HouseRules,
+	}
+	for _, h := range s.Heroes {
+		e.Heroes = append(e.Heroes, h.Name)
+	}
+	if len(s.Seating) > 0 {
+		e.Seats = make(map[string]string)
+		for _, st := range s.Seating {
+			e.Seats[st.Hero.Name] = st.Player
+		}
+	}
+	return e
+}
@@ -0,0 +1,179 @@
+package httpserver
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// auditEntry records a change made through the admin routes.
+type auditEntry struct {
+	Time   time.Time
+	Remote string // address of the client
+	Action string
+	Detail string
+}
+
+// auditLog keeps the admin changes made since the server started and, if
+// path is set, appends them to that file as JSON lines.
+type auditLog struct {
+	mu      sync.Mutex
+	path    string
+	entries []*auditEntry
+}
+
+// add records a change.
+func (al *auditLog) add(r *http.Request, action, detail string) {
+	e := &auditEntry{Time: sentinels.Now(), Remote: r.RemoteAddr, Action: action, Detail: detail}
+	log.Printf("admin: %s: %s", action, detail)
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.entries = append(al.entries, e)
+	if al.path == "" {
+		return
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	f, err := os.OpenFile(al.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("Couldn't write audit log: %v", err)
+		return
+	}
+	defer f.Close()
+	f.Write(append(b, '\n'))
+}
+
+// adminCookie holds the admin token in browsers, for the admin pages.
+const adminCookie = "admintoken"
+
+// admin wraps a handler for an admin route, requiring the configured admin
+// token as a bearer token or, for browsers, in a "token" query parameter,
+// which also sets a cookie for the admin routes that keeps working.
+func (sv *server) admin(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if sv.config.AdminToken == "" {
+			http.Error(w, "Admin routes are disabled.", http.StatusForbidden)
+			return
+		}
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if t := r.URL.Query().Get("token"); t != "" && equal(t, sv.config.AdminToken) {
+			http.SetCookie(w, &http.Cookie{Name: adminCookie, Value: t, Path: "/admin/", HttpOnly: true, SameSite: http.SameSiteStrictMode})
+			given = t
+		} else if ck, err := r.Cookie(adminCookie); err == nil && given == "" {
+			given = ck.Value
+		}
+		if subtle.ConstantTimeCompare([]byte(given), []byte(sv.config.AdminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized.", http.StatusUnauthorized)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// loadData loads the configured card data and returns a description of it
+// for the audit log.
+func (sv *server) loadData() (string, error) {
+	data, overlays, custom, desc, err := sv.readData()
+	if err != nil {
+		return "", err
+	}
+	if custom != nil {
+		overlays = append(overlays, custom)
+	}
+	if err := sentinels.LoadLayers(data, overlays...); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s; %d cards", strings.Join(desc, ", "), len(sentinels.AllCards())), nil
+}
+
+// readData reads the configured card data, overlays and custom cards,
+// which are nil if not configured, and describes them for the audit log.  A
+// missing custom cards file reads as none, so that the data editor can
+// create it.
+func (sv *server) readData() (data []byte, overlays [][]byte, custom []byte, desc []string, err error) {
+	type source struct {
+		path string
+		b    *[]byte
+	}
+	sources := []source{{sv.config.Data, &data}}
+	overlays = make([][]byte, len(sv.config.Overlays))
+	for i, path := range sv.config.Overlays {
+		sources = append(sources, source{path, &overlays[i]})
+	}
+	sources = append(sources, source{sv.config.Custom, &custom})
+	for _, f := range sources {
+		if f.path == "" {
+			continue
+		}
+		b, err := readSource(f.path)
+		if os.IsNotExist(err) && f.b == &custom {
+			continue
+		}
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		*f.b = b
+		desc = append(desc, fmt.Sprintf("%s (sha256 %x)", f.path, sha256.Sum256(b)))
+	}
+	if data == nil {
+		desc = append([]string{"built-in data"}, desc...)
+	} else if sv.config.RepairScale {
+		if data, err = sentinels.RepairData(data); err != nil {
+			return nil, nil, nil, nil, err
+		}
+		desc = append(desc, "scale repaired")
+	}
+	return data, overlays, custom, desc, nil
+}
+
+// reloadData reloads the card data from the configured files.
+func (sv *server) reloadData(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "POST to reload", http.StatusMethodNotAllowed)
+		return
+	}
+	detail, err := sv.loadData()
+	if err != nil {
+		sv.audit.add(r, "reload data failed", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sv.audit.add(r, "reload data", detail)
+	fmt.Fprintln(w, detail)
+}
+
+// reloadTemplates reparses the HTML templates.
+func (sv *server) reloadTemplates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "POST to reload", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := sv.parseTemplates(); err != nil {
+		sv.audit.add(r, "reload templates failed", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sv.audit.add(r, "reload templates", sv.config.Templates)
+	fmt.Fprintln(w, "Templates reloaded.")
+}
+
+// showAudit responds with the admin changes made since the server started.
+func (sv *server) showAudit(w http.ResponseWriter, r *http.Request) {
+	sv.audit.mu.Lock()
+	defer sv.audit.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sv.audit.entries)
+}
@@ -0,0 +1,23 @@
+//go:build appengine
+// +build appengine
+
+package httpserver
+
+import (
+	"log"
+	"net/http"
+)
+
+// On App Engine the runtime serves http.DefaultServeMux, so register the
+// app there.
+func init() {
+	c, err := ConfigFromFile("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	h, err := NewHandler(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	http.Handle("/", h)
+}
@@ -0,0 +1,16 @@
+package httpserver
+
+import (
+	"embed"
+	"net/http"
+)
+
+// assets holds the templates and static files, so that a server binary can
+// run without the source tree.  A Config's Templates directory, if set,
+// takes the templates' place, e.g. to edit them without rebuilding.
+//
+//go:embed *.html css svg
+var assets embed.FS
+
+// staticFiles serves the embedded css and svg directories.
+var staticFiles = http.FileServer(http.FS(assets))
@@ -0,0 +1,60 @@
+package httpserver
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// authCookie holds the access token in browsers, which can't send a bearer
+// token themselves.
+const authCookie = "token"
+
+// equal compares secrets in constant time.
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// requireAuth wraps h so that, if the configuration sets AuthToken or
+// BasicAuth, only clients presenting one of them get through.  The token is
+// accepted as a bearer token, in a "token" query parameter (which also sets
+// a cookie, so a bookmarked link keeps working) or in that cookie.  The
+// admin routes are left to their own token.
+func requireAuth(c *Config, h http.Handler) http.Handler {
+	if c.AuthToken == "" && c.BasicAuth == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/admin/") || authorized(c, w, r) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if c.BasicAuth != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="sentinels"`)
+		}
+		http.Error(w, "Unauthorized.", http.StatusUnauthorized)
+	})
+}
+
+// authorized reports whether the request carries valid credentials.
+func authorized(c *Config, w http.ResponseWriter, r *http.Request) bool {
+	if c.BasicAuth != "" {
+		if u, p, ok := r.BasicAuth(); ok && equal(u+":"+p, c.BasicAuth) {
+			return true
+		}
+	}
+	if c.AuthToken == "" {
+		return false
+	}
+	if h := r.Header.Get("Authorization"); strings.HasPrefix(h, "Bearer ") && equal(strings.TrimPrefix(h, "Bearer "), c.AuthToken) {
+		return true
+	}
+	if t := r.URL.Query().Get("token"); t != "" && equal(t, c.AuthToken) {
+		http.SetCookie(w, &http.Cookie{Name: authCookie, Value: t, Path: "/", HttpOnly: true})
+		return true
+	}
+	if ck, err := r.Cookie(authCookie); err == nil && equal(ck.Value, c.AuthToken) {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// Limits on bulk generation, so that a client asking for many setups can't
+// starve the others.
+const (
+	DefaultBulkCount = 10  // setups per page if "count" isn't given
+	MaxBulkCount     = 200 // most setups per page
+	maxBulkRequests  = 2   // bulk requests served at once
+)
+
+// bulkSlots holds a token for each bulk request being served.
+var bulkSlots = make(chan struct{}, maxBulkRequests)
+
+// bulkLine is one line of a bulk response.
+type bulkLine struct {
+	Index int              // position in the whole sequence, counting from 0
+	Setup *sentinels.Setup `json:",omitempty"`
+	Error string           `json:",omitempty"`
+}
+
+// bulkSetups streams a page of setups as newline-delimited JSON, one per
+// line, for tools that want many samples.  The parameters are "pc", "lp",
+// "rg", "exp" and "adv", as in the form; "count" setups are sent per page
+// and "page" (from 1) picks the page.  Setup i of the sequence is found
+// with seed+i, so the pages of a "seed" are reproducible; without one a
+// seed is chosen, and the Link header gives the next page.  Samples are
+// not recorded in the history.
+func (sv *server) bulkSetups(w http.ResponseWriter, r *http.Request) {
+	p, count, page, seed, err := sv.bulkParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	select {
+	case bulkSlots <- struct{}{}:
+		defer func() { <-bulkSlots }()
+	default:
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "Too many bulk requests; try again shortly.", http.StatusTooManyRequests)
+		return
+	}
+
+	next := *r.URL
+	q := next.Query()
+	q.Set("seed", strconv.FormatInt(seed, 10))
+	q.Set("page", strconv.Itoa(page+1))
+	next.RawQuery = q.Encode()
+	w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", (&url.URL{Path: next.Path, RawQuery: next.RawQuery}).String()))
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	for i := (page - 1) * count; i < page*count; i++ {
+		select {
+		case <-r.Context().Done():
+			return
+		default:
+		}
+		q := *p
+		line := bulkLine{Index: i}
+		if line.Setup, err = sv.engine.Sample(&q, seed+int64(i)); err != nil {
+			line.Error = err.Error()
+		}
+		if err := enc.Encode(line); err != nil {
+			log.Println(err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if line.Error != "" {
+			return
+		}
+	}
+}
+
+// bulkParams reads the search parameters and paging of a bulk request.
+func (sv *server) bulkParams(r *http.Request) (p *sentinels.Params, count, page int, seed int64, err error) {
+	p = &sentinels.Params{Players: sv.config.Players, LossPercent: 50, Range: 10, Expansions: sv.config.Expansions}
+	count, page = DefaultBulkCount, 1
+	for _, f := range []struct {
+		name     string
+		v        *int
+		min, max int
+	}{
+		{"pc", &p.Players, 3, 5},
+		{"lp", &p.LossPercent, 1, 99},
+		{"rg", &p.Range, 0, 100},
+		{"count", &count, 1, MaxBulkCount},
+		{"page", &page, 1, 1 << 20},
+	} {
+		s := r.FormValue(f.name)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < f.min || n > f.max {
+			return nil, 0, 0, 0, fmt.Errorf("%s must be %d to %d, not %q.", f.name, f.min, f.max, s)
+		}
+		*f.v = n
+	}
+	if v := r.FormValue("exp"); v != "" {
+		if p.Expansions, err = sentinels.ParseExpansions(v); err != nil {
+			return nil, 0, 0, 0, err
+		}
+	}
+	p.Advanced = r.FormValue("adv") == "on" || r.FormValue("adv") == "true"
+	if v := r.FormValue("seed"); v != "" {
+		if seed, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, 0, 0, 0, fmt.Errorf("Bad seed %q.", v)
+		}
+	} else {
+		seed = sentinels.NewSeed() % (1 << 53)
+	}
+	return p, count, page, seed, nil
+}
@@ -0,0 +1,95 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// dataMaxAge is how long, in seconds, clients may cache responses that
+// depend only on the card data before revalidating them.
+const dataMaxAge = 3600
+
+// notModified sets cache headers on a response that depends only on the
+// card data and the variant named, such as its format, and reports whether
+// the client's copy is current, in which case it has answered 304 Not
+// Modified.  The ETag is the data version, so it changes whenever data is
+// loaded that differs.
+func notModified(w http.ResponseWriter, r *http.Request, variant string) bool {
+	tag := sentinels.DataVersion()
+	if variant != "" {
+		tag += "-" + variant
+	}
+	tag = `"` + tag + `"`
+	mod := sentinels.DataModified().UTC().Truncate(time.Second)
+	h := w.Header()
+	h.Set("ETag", tag)
+	h.Set("Last-Modified", mod.Format(http.TimeFormat))
+	h.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", dataMaxAge))
+	h.Add("Vary", "Accept")
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, t := range strings.Split(inm, ",") {
+			if t = strings.TrimSpace(t); t == tag || t == "W/"+tag || t == "*" {
+				w.WriteHeader(http.StatusNotModified)
+				return true
+			}
+		}
+		return false
+	}
+	if t, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !mod.After(t) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// expansionInfo describes an expansion for the expansions API.
+type expansionInfo struct {
+	Name         string // the value to pass as "exp"
+	Title        string
+	Heroes       int
+	Villains     int
+	Environments int
+}
+
+// bundleInfo describes a bundle of expansions for the bundles API.
+type bundleInfo struct {
+	Name       string // the value to pass as "exp"
+	Title      string
+	Expansions []string
+}
+
+// bundlesAPI sends the bundles of expansions, named as the digital
+// edition's DLC is, as JSON.
+func bundlesAPI(w http.ResponseWriter, r *http.Request) {
+	var bs []*bundleInfo
+	for _, b := range sentinels.Bundles {
+		bi := &bundleInfo{Name: b.Name, Title: b.Title}
+		for _, e := range b.Expansions {
+			bi.Expansions = append(bi.Expansions, e.String())
+		}
+		bs = append(bs, bi)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bs)
+}
+
+// expansionsAPI sends the expansions and how many cards of each type they
+// hold as JSON.
+func expansionsAPI(w http.ResponseWriter, r *http.Request) {
+	if notModified(w, r, "") {
+		return
+	}
+	var es []*expansionInfo
+	for i, n := range sentinels.ExpansionNames {
+		e := sentinels.ExpansionType(i)
+		cs := sentinels.Select(sentinels.WithExpansions(e))
+		es = append(es, &expansionInfo{n, e.Title(), len(cs.Heroes), len(cs.Villains), len(cs.Environments)})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(es)
+}
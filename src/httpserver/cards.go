@@ -0,0 +1,314 @@
+package httpserver
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// cardRow is a card as the cards API sends it.
+type cardRow struct {
+	Name       string
+	Type       string // "hero", "villain" or "environment"
+	Expansion  sentinels.ExpansionType
+	Points     int
+	Advanced   int      `json:",omitempty"`
+	AdvCount   int      `json:",omitempty"`
+	Base       string   `json:",omitempty"` // only for variants
+	Tags       []string `json:",omitempty"`
+	Pool       string   `json:",omitempty"`
+	Minutes    int      `json:",omitempty"`
+	Complexity int      `json:",omitempty"`
+	Archetype  string   `json:",omitempty"`
+	Nemesis    string   `json:",omitempty"`
+}
+
+// cardColumns are the CSV columns, in the order of cardRow's fields.
+var cardColumns = []string{"name", "type", "expansion", "points", "advanced", "advcount", "base", "tags", "pool", "minutes", "complexity", "archetype", "nemesis"}
+
+// cardTypeNames are the values of the cards API's "type" parameter.
+var cardTypeNames = map[sentinels.CardType]string{
+	sentinels.Hero:        "hero",
+	sentinels.Villain:     "villain",
+	sentinels.Environment: "environment",
+}
+
+func newCardRow(c *sentinels.Card) *cardRow {
+	r := &cardRow{c.Name, cardTypeNames[c.Type], c.Expansion, c.Points, c.Advanced, c.AdvCount, c.Base,
+		c.Tags, c.Pool, c.Minutes, c.Complexity, c.Archetype, c.Nemesis}
+	if r.Base == r.Name {
+		r.Base = ""
+	}
+	return r
+}
+
+// csv returns the row's CSV fields.
+func (r *cardRow) csv() []string {
+	itoa := strconv.Itoa
+	return []string{r.Name, r.Type, r.Expansion.String(), itoa(r.Points), itoa(r.Advanced), itoa(r.AdvCount), r.Base,
+		strings.Join(r.Tags, ";"), r.Pool, itoa(r.Minutes), itoa(r.Complexity), r.Archetype, r.Nemesis}
+}
+
+// cardFilter selects cards by the cards API's query parameters.
+type cardFilter struct {
+	types    map[string]bool // empty means any
+	exp      []sentinels.ExpansionType
+	tag      string
+	pool     string
+	q        string // a CardKey
+	min, max int
+	sort     string // "name", "points" or "-points"; see rows
+}
+
+// parseCardFilter reads the cards API's filters: "type" (a comma-separated
+// list of hero, villain and environment), "exp" (all expansions if not
+// given), "tag", "pool", "q" (part of the name, ignoring case, spaces and
+// punctuation), "min" and "max" points and "sort": "name", "points" for the
+// easiest first or "-points" for the hardest first.
+func parseCardFilter(r *http.Request) (*cardFilter, error) {
+	f := &cardFilter{types: make(map[string]bool),
+		tag: r.FormValue("tag"), pool: r.FormValue("pool"), q: sentinels.CardKey(r.FormValue("q")),
+		min: -sentinels.MaxPoints, max: sentinels.MaxPoints, sort: r.FormValue("sort")}
+	switch f.sort {
+	case "", "name", "points", "-points":
+	default:
+		return nil, fmt.Errorf("Unknown sort %q; use name, points or -points.", f.sort)
+	}
+	if v := r.FormValue("type"); v != "" {
+		for _, t := range strings.Split(v, ",") {
+			switch t = strings.TrimSpace(t); t {
+			case "hero", "villain", "environment":
+				f.types[t] = true
+			default:
+				return nil, fmt.Errorf("Unknown card type %q.", t)
+			}
+		}
+	}
+	exp := r.FormValue("exp")
+	if exp == "" {
+		exp = strings.Join(sentinels.ExpansionNames, ",")
+	}
+	var err error
+	if f.exp, err = sentinels.ParseExpansions(exp); err != nil {
+		return nil, err
+	}
+	for _, b := range []struct {
+		name string
+		v    *int
+	}{{"min", &f.min}, {"max", &f.max}} {
+		if s := r.FormValue(b.name); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a number of points, not %q.", b.name, s)
+			}
+			*b.v = n
+		}
+	}
+	return f, nil
+}
+
+// rows returns the matching cards: heroes, then villains, then
+// environments, each by name, unless the filter sorts them otherwise.
+func (f *cardFilter) rows() []*cardRow {
+	cs := sentinels.Select(sentinels.WithExpansions(f.exp...))
+	var rows []*cardRow
+	for _, l := range [][]*sentinels.Card{cs.Heroes, cs.Villains, cs.Environments} {
+		for _, c := range l {
+			if f.match(c) {
+				rows = append(rows, newCardRow(c))
+			}
+		}
+	}
+	switch f.sort {
+	case "name":
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+	case "points":
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].Points < rows[j].Points })
+	case "-points":
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].Points > rows[j].Points })
+	}
+	return rows
+}
+
+// match reports whether a card passes the filter.
+func (f *cardFilter) match(c *sentinels.Card) bool {
+	if len(f.types) > 0 && !f.types[cardTypeNames[c.Type]] {
+		return false
+	}
+	if f.pool != "" && c.Pool != f.pool {
+		return false
+	}
+	if f.q != "" && !strings.Contains(sentinels.CardKey(c.Name), f.q) {
+		return false
+	}
+	if c.Points < f.min || c.Points > f.max {
+		return false
+	}
+	if f.tag == "" {
+		return true
+	}
+	for _, t := range c.Tags {
+		if t == f.tag {
+			return true
+		}
+	}
+	return false
+}
+
+// cardsFormat returns the format the cards API should answer in: the
+// "format" parameter, if given, or else the first of CSV and NDJSON the
+// Accept header names, or else JSON.
+func cardsFormat(r *http.Request) (string, error) {
+	switch f := r.FormValue("format"); f {
+	case "json", "ndjson", "csv":
+		return f, nil
+	case "":
+	default:
+		return "", fmt.Errorf("Unknown format %q; use json, ndjson or csv.", f)
+	}
+	for _, a := range strings.Split(r.Header.Get("Accept"), ",") {
+		switch strings.TrimSpace(strings.Split(a, ";")[0]) {
+		case "text/csv":
+			return "csv", nil
+		case "application/x-ndjson", "application/ndjson":
+			return "ndjson", nil
+		case "application/json":
+			return "json", nil
+		}
+	}
+	return "json", nil
+}
+
+// cardsAPI sends the cards matching the request's filters as a JSON array,
+// newline-delimited JSON or CSV; see parseCardFilter and cardsFormat.
+func cardsAPI(w http.ResponseWriter, r *http.Request) {
+	f, err := parseCardFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	format, err := cardsFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if notModified(w, r, format) {
+		return
+	}
+	rows := f.rows()
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="cards.csv"`)
+		cw := csv.NewWriter(w)
+		cw.Write(cardColumns)
+		for _, row := range rows {
+			cw.Write(row.csv())
+		}
+		cw.Flush()
+	case "ndjson":
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		for _, row := range rows {
+			enc.Encode(row)
+		}
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		if rows == nil {
+			rows = []*cardRow{}
+		}
+		json.NewEncoder(w).Encode(rows)
+	}
+}
+
+// cardsPageSize is the number of cards the cards page shows at a time.
+const cardsPageSize = 25
+
+// cardsTab is one of the cards page's type tabs.
+type cardsTab struct {
+	Label   string
+	URL     template.URL
+	Current bool
+}
+
+// cardsPage is the data for the cards template.
+type cardsPage struct {
+	Rows       []*cardRow
+	Total      int
+	First      int        // position of the first row shown, from 1
+	Last       int        // position of the last row shown
+	Query      url.Values // the filters, to fill in the form
+	Tabs       []cardsTab
+	Prev, Next template.URL // the neighboring pages, if any
+	API        template.URL // the same cards from the cards API
+	Expansions []sentinels.ExpansionType
+	Msg        string
+	Lang       string
+}
+
+// cards lets users browse the cards a page at a time, filtered as in
+// parseCardFilter and starting at "offset".
+func (sv *server) cards(w http.ResponseWriter, r *http.Request) {
+	page := &cardsPage{Query: r.URL.Query(), Lang: requestLang(r), API: template.URL("/api/cards?" + r.URL.RawQuery)}
+	for e := range sentinels.ExpansionNames {
+		page.Expansions = append(page.Expansions, sentinels.ExpansionType(e))
+	}
+	link := func(key, value string) template.URL {
+		v := r.URL.Query()
+		if value == "" {
+			v.Del(key)
+		} else {
+			v.Set(key, value)
+		}
+		if key != "offset" {
+			v.Del("offset")
+		}
+		return template.URL("/cards?" + v.Encode())
+	}
+	for _, t := range []struct{ label, value string }{{"All", ""}, {"Heroes", "hero"}, {"Villains", "villain"}, {"Environments", "environment"}} {
+		page.Tabs = append(page.Tabs, cardsTab{t.label, link("type", t.value), r.FormValue("type") == t.value})
+	}
+	f, err := parseCardFilter(r)
+	if err != nil {
+		page.Msg = err.Error()
+		sv.render(w, "cards.html", page)
+		return
+	}
+	offset := 0
+	if v := r.FormValue("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			page.Msg = fmt.Sprintf("The offset must be a number of cards, not %q.", v)
+			sv.render(w, "cards.html", page)
+			return
+		}
+	}
+	rows := f.rows()
+	page.Total = len(rows)
+	if offset > len(rows) {
+		offset = len(rows)
+	}
+	end := offset + cardsPageSize
+	if end > len(rows) {
+		end = len(rows)
+	}
+	page.Rows, page.First, page.Last = rows[offset:end], offset+1, end
+	if offset > 0 {
+		prev := offset - cardsPageSize
+		if prev < 0 {
+			prev = 0
+		}
+		page.Prev = link("offset", strconv.Itoa(prev))
+	}
+	if end < len(rows) {
+		page.Next = link("offset", strconv.Itoa(end))
+	}
+	sv.render(w, "cards.html", page)
+}
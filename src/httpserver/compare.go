@@ -0,0 +1,67 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// comparePage is the data for the comparison template.
+type comparePage struct {
+	A, B string // the setups' share links, as entered
+	Lang string
+	C    *sentinels.Comparison
+	Msg  string
+}
+
+// parseComparison scores the setups in the "a" and "b" parameters, each a
+// share link or just its query, and compares them.
+func parseComparison(r *http.Request) (*sentinels.Comparison, error) {
+	var s [2]*sentinels.Setup
+	for i, n := range []string{"a", "b"} {
+		q := r.FormValue(n)
+		if j := strings.Index(q, "?"); j >= 0 {
+			q = q[j+1:]
+		}
+		var err error
+		if s[i], err = sentinels.ParseShareQuery(q); err != nil {
+			return nil, fmt.Errorf("Setup %s: %v", strings.ToUpper(n), err)
+		}
+	}
+	return sentinels.Compare(s[0], s[1]), nil
+}
+
+// compare shows two setups side by side.
+func (sv *server) compare(w http.ResponseWriter, r *http.Request) {
+	p := &comparePage{A: r.FormValue("a"), B: r.FormValue("b"), Lang: requestLang(r)}
+	if p.A != "" || p.B != "" {
+		c, err := parseComparison(r)
+		if err != nil {
+			p.Msg = err.Error()
+		} else {
+			c.A.Lang, c.B.Lang = p.Lang, p.Lang
+			p.C = c
+		}
+	}
+	sv.render(w, "compare.html", p)
+}
+
+// compareAPI sends the comparison of two setups as JSON.
+func compareAPI(w http.ResponseWriter, r *http.Request) {
+	c, err := parseComparison(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c)
+}
+
+// compareURL links to the comparison of two setups.
+func compareURL(a, b *sentinels.Setup) string {
+	return "/compare?" + url.Values{"a": {a.ShareQuery()}, "b": {b.ShareQuery()}}.Encode()
+}
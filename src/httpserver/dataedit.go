@@ -0,0 +1,142 @@
+package httpserver
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// editRow is a card on the data editor.
+type editRow struct {
+	Name     string
+	Type     string
+	Points   int
+	Advanced int
+}
+
+// editPage is the data for the data editor template.
+type editPage struct {
+	Rows    []editRow
+	Changes []sentinels.CardEdit // the overlay's changes from the base data
+	Preview bool                 // Changes are of the posted values, not yet saved
+	Path    string               // custom cards file the overlay is saved to
+	Msg     string
+	Lang    string
+}
+
+// editData serves the data editor, on which an admin sets the points of
+// cards.  Posting "preview" shows how the values differ from the base data,
+// and "save" also writes the changes to the custom cards file as an
+// overlay and reloads the data.  Values are checked as the data is when it
+// loads.
+func (sv *server) editData(w http.ResponseWriter, r *http.Request) {
+	page := &editPage{Path: sv.config.Custom, Lang: requestLang(r)}
+	for _, c := range sentinels.AllCards() {
+		page.Rows = append(page.Rows, editRow{c.Name, cardTypeNames[c.Type], c.Points, c.Advanced})
+	}
+	sort.Slice(page.Rows, func(i, j int) bool {
+		if page.Rows[i].Type != page.Rows[j].Type {
+			return page.Rows[i].Type < page.Rows[j].Type
+		}
+		return page.Rows[i].Name < page.Rows[j].Name
+	})
+	data, overlays, custom, _, err := sv.readData()
+	if err != nil {
+		page.Msg = err.Error()
+		sv.render(w, "dataedit.html", page)
+		return
+	}
+	edits := make(map[string]sentinels.CardValues)
+	if r.Method == "POST" {
+		for i := range page.Rows {
+			row := &page.Rows[i]
+			if err := formInt(r, "points:"+row.Name, &row.Points); err != nil {
+				page.Msg = err.Error()
+			}
+			if err := formInt(r, "adv:"+row.Name, &row.Advanced); err != nil {
+				page.Msg = err.Error()
+			}
+			edits[row.Name] = sentinels.CardValues{Points: row.Points, Advanced: row.Advanced}
+		}
+	}
+	if page.Msg != "" {
+		sv.render(w, "dataedit.html", page)
+		return
+	}
+	overlay, changes, err := sentinels.EditOverlay(data, overlays, custom, edits)
+	page.Changes, page.Preview = changes, r.Method == "POST"
+	if err != nil {
+		page.Msg = err.Error()
+	} else if r.FormValue("save") != "" {
+		page.Msg = sv.saveOverlay(r, overlay)
+		page.Preview = page.Msg != ""
+	}
+	sv.render(w, "dataedit.html", page)
+}
+
+// saveOverlay writes an overlay from the data editor to the custom cards
+// file and reloads the data, returning a message if that fails.
+func (sv *server) saveOverlay(r *http.Request, overlay []byte) string {
+	path := sv.config.Custom
+	if path == "" || isURL(path) {
+		return "Edits can only be saved to a custom cards file; set SENTINELS_CUSTOM to one."
+	}
+	if err := ioutil.WriteFile(path, overlay, 0644); err != nil {
+		return err.Error()
+	}
+	detail, err := sv.loadData()
+	if err != nil {
+		sv.audit.add(r, "edit data failed", err.Error())
+		return err.Error()
+	}
+	sv.audit.add(r, "edit data", detail)
+	return ""
+}
+
+// restoreCustom returns a function that checks custom cards from an
+// imported archive against the rest of the configured data, writes them to
+// the custom cards file and reloads the data.
+func (sv *server) restoreCustom(r *http.Request) func([]byte) error {
+	return func(custom []byte) error {
+		path := sv.config.Custom
+		if path == "" || isURL(path) {
+			return errors.New("Custom cards can only be restored to a custom cards file; set SENTINELS_CUSTOM to one.")
+		}
+		data, overlays, _, _, err := sv.readData()
+		if err != nil {
+			return err
+		}
+		if err := sentinels.CheckLayers(data, append(overlays, custom)...); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, custom, 0644); err != nil {
+			return err
+		}
+		detail, err := sv.loadData()
+		if err != nil {
+			sv.audit.add(r, "import data failed", err.Error())
+			return err
+		}
+		sv.audit.add(r, "import data", detail)
+		return nil
+	}
+}
+
+// formInt sets *v from the named form value, if it is present.
+func formInt(r *http.Request, name string, v *int) error {
+	s := r.FormValue(name)
+	if s == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("Bad value %q for %s.", s, name)
+	}
+	*v = n
+	return nil
+}
@@ -0,0 +1,6 @@
+// Package httpserver serves the sentinels engine over HTTP.  NewHandler
+// returns the app as an http.Handler to mount anywhere; ListenAndServe runs
+// it as a standalone server, and the sentinels-server command wraps it for
+// containers.  Only when built for App Engine does the package register
+// itself on http.DefaultServeMux.
+package httpserver
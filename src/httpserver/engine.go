@@ -0,0 +1,58 @@
+package httpserver
+
+import (
+	"context"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// Engine generates the setups the app shows.  The app uses DefaultEngine
+// unless Config.Engine is set, so that it can be run against a fake engine
+// that returns fixed setups or errors.
+type Engine interface {
+	// Find gives up with ctx's error if ctx is done first, e.g. when the
+	// client has gone.
+	Find(ctx context.Context, p *sentinels.Params) (*sentinels.Setup, int, error)
+	Veto(token string) (*sentinels.Setup, int, error)
+	DealDraft(p *sentinels.Params, k int) (*sentinels.Draft, error)
+	FinishDraft(p *sentinels.Params, picks []*sentinels.Card) (*sentinels.Setup, int, error)
+	// Reissue records a copy of a setup Find returned as a new one, with its
+	// own token, so that a repeated search can reuse it.
+	Reissue(s *sentinels.Setup) *sentinels.Setup
+	// Sample returns the setup p gives from seed without recording it, for
+	// bulk generation and the quiz.
+	Sample(p *sentinels.Params, seed int64) (*sentinels.Setup, error)
+}
+
+// DefaultEngine generates setups with the sentinels package.
+type DefaultEngine struct{}
+
+// Find implements Engine.
+func (DefaultEngine) Find(ctx context.Context, p *sentinels.Params) (*sentinels.Setup, int, error) {
+	return sentinels.Generate(ctx, sentinels.WithParams(p))
+}
+
+// Veto implements Engine.
+func (DefaultEngine) Veto(token string) (*sentinels.Setup, int, error) {
+	return sentinels.Veto(token)
+}
+
+// DealDraft implements Engine.
+func (DefaultEngine) DealDraft(p *sentinels.Params, k int) (*sentinels.Draft, error) {
+	return sentinels.DealDraft(p, k)
+}
+
+// FinishDraft implements Engine.
+func (DefaultEngine) FinishDraft(p *sentinels.Params, picks []*sentinels.Card) (*sentinels.Setup, int, error) {
+	return sentinels.FinishDraft(p, picks)
+}
+
+// Reissue implements Engine.
+func (DefaultEngine) Reissue(s *sentinels.Setup) *sentinels.Setup {
+	return sentinels.Reissue(s)
+}
+
+// Sample implements Engine.
+func (DefaultEngine) Sample(p *sentinels.Params, seed int64) (*sentinels.Setup, error) {
+	return sentinels.Sample(p, seed)
+}
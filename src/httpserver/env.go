@@ -0,0 +1,182 @@
+package httpserver
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uhhhclem/sentinels"
+	"github.com/uhhhclem/sentinels/config"
+	"github.com/uhhhclem/sentinels/storage"
+)
+
+// ConfigFromEnv reads the configuration from the environment, so that the
+// app can be deployed in a container without a configuration file.  Unset
+// values get defaults suited to running in the app's own directory.  The
+// variables are:
+//
+//	SENTINELS_ADDR         listen address, e.g. ":8080"; overrides PORT
+//	PORT                   listen port, as set by Cloud Run and similar platforms
+//	SENTINELS_TEMPLATES    directory holding the HTML templates, if not the embedded ones
+//	SENTINELS_PROFILES     profiles file; set but empty keeps them in memory
+//	SENTINELS_HISTORY      history file
+//	SENTINELS_DB_PATH      directory for the profiles and history files or,
+//	                       with SENTINELS_DB_DRIVER, the database to open
+//	SENTINELS_DB_DRIVER    database/sql driver to keep them in a database
+//	SENTINELS_STORAGE      "memory" to keep profiles and history in memory
+//	SENTINELS_DATA         card data file; see sentinels.LoadData
+//	SENTINELS_DATA_URL     URL to fetch the card data from instead
+//	SENTINELS_CUSTOM       custom cards file
+//	SENTINELS_CUSTOM_URL   URL to fetch the custom cards from instead
+//	SENTINELS_OVERLAYS     comma-separated overlay files applied before the custom cards
+//	SENTINELS_REPAIR_SCALE repair the data's scale (any non-empty value)
+//	SENTINELS_PLAYERS      the form's default number of heroes
+//	SENTINELS_EXPANSIONS   the form's default expansions, comma-separated
+//	SENTINELS_ADMIN_TOKEN  bearer token for the admin routes
+//	SENTINELS_AUDIT_LOG    file admin changes are appended to
+//	SENTINELS_AUTH_TOKEN   bearer token required to use the app
+//	SENTINELS_BASIC_AUTH   "user:password" required to use the app
+//	SENTINELS_SCHEDULE     scheduler configuration file
+//	SENTINELS_WEBHOOKS     comma-separated URLs sent generated and played setups
+//	SENTINELS_SEED         run deterministically from this seed; see Config.Seed
+//
+// Bad values are logged and ignored.
+func ConfigFromEnv() *Config {
+	c := &Config{
+		Profiles:   "profiles.json",
+		Addr:       ":8080",
+		Players:    3,
+		Expansions: []sentinels.ExpansionType{sentinels.BaseSet},
+	}
+	if err := c.readEnv(); err != nil {
+		log.Println(err)
+	}
+	return c
+}
+
+// readEnv overrides the configuration with the environment variables that
+// are set; see ConfigFromEnv.  A variable with a bad value is skipped, and
+// the others still read; the error reports every bad value.
+func (c *Config) readEnv() error {
+	var errs []error
+	for _, v := range []struct {
+		name string
+		p    *string
+	}{
+		{"SENTINELS_TEMPLATES", &c.Templates},
+		{"SENTINELS_HISTORY", &c.History},
+		{"SENTINELS_DATA", &c.Data},
+		{"SENTINELS_DATA_URL", &c.Data},
+		{"SENTINELS_CUSTOM", &c.Custom},
+		{"SENTINELS_CUSTOM_URL", &c.Custom},
+		{"SENTINELS_ADMIN_TOKEN", &c.AdminToken},
+		{"SENTINELS_AUDIT_LOG", &c.AuditLog},
+		{"SENTINELS_AUTH_TOKEN", &c.AuthToken},
+		{"SENTINELS_BASIC_AUTH", &c.BasicAuth},
+		{"SENTINELS_SCHEDULE", &c.Schedule},
+	} {
+		if s := os.Getenv(v.name); s != "" {
+			*v.p = s
+		}
+	}
+	if os.Getenv("SENTINELS_REPAIR_SCALE") != "" {
+		c.RepairScale = true
+	}
+	if v := os.Getenv("SENTINELS_OVERLAYS"); v != "" {
+		c.Overlays = strings.Split(v, ",")
+	}
+	if v := os.Getenv("SENTINELS_WEBHOOKS"); v != "" {
+		c.Webhooks = strings.Split(v, ",")
+	}
+	if v, ok := os.LookupEnv("SENTINELS_PROFILES"); ok {
+		c.Profiles = v
+	}
+	if port := os.Getenv("PORT"); port != "" {
+		c.Addr = ":" + port
+	}
+	if v := os.Getenv("SENTINELS_ADDR"); v != "" {
+		c.Addr = v
+	}
+	if v := os.Getenv("SENTINELS_PLAYERS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 3 || n > 5 {
+			errs = append(errs, fmt.Errorf("SENTINELS_PLAYERS must be 3 to 5, not %q.", v))
+		} else {
+			c.Players = n
+		}
+	}
+	if v := os.Getenv("SENTINELS_SEED"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("SENTINELS_SEED must be a number, not %q.", v))
+		} else {
+			c.Seed = n
+		}
+	}
+	if v := os.Getenv("SENTINELS_EXPANSIONS"); v != "" {
+		exp, err := sentinels.ParseExpansions(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("SENTINELS_EXPANSIONS: %v", err))
+		} else {
+			c.Expansions = exp
+		}
+	}
+	if err := c.readStorageEnv(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// readStorageEnv sets where profiles and history are kept from
+// SENTINELS_STORAGE, SENTINELS_DB_DRIVER and SENTINELS_DB_PATH.
+func (c *Config) readStorageEnv() error {
+	path, driver := os.Getenv("SENTINELS_DB_PATH"), os.Getenv("SENTINELS_DB_DRIVER")
+	switch st := os.Getenv("SENTINELS_STORAGE"); {
+	case st == "memory":
+		c.Store = &storage.MemoryStore{}
+	case st != "":
+		return fmt.Errorf("SENTINELS_STORAGE must be \"memory\", not %q.", st)
+	case driver != "":
+		var err error
+		c.Store, err = (&config.Storage{Backend: "sql", Driver: driver, DSN: path}).Open()
+		if err != nil {
+			return fmt.Errorf("SENTINELS_DB_DRIVER: %v", err)
+		}
+	case path != "":
+		c.Store = nil
+		c.Profiles = filepath.Join(path, "profiles.json")
+		c.History = filepath.Join(path, "history.json")
+	}
+	return nil
+}
+
+// isURL reports whether a data source is a URL rather than a file.
+func isURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// dataClient fetches card data from URLs.
+var dataClient = &http.Client{Timeout: 30 * time.Second}
+
+// readSource reads a data file or fetches a data URL.
+func readSource(path string) ([]byte, error) {
+	if !isURL(path) {
+		return ioutil.ReadFile(path)
+	}
+	resp, err := dataClient.Get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Fetching %s: %s", path, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,14 @@
+package httpserver
+
+import "testing"
+
+func TestConfigFromEnvSkipsBadValues(t *testing.T) {
+	t.Setenv("SENTINELS_PLAYERS", "seven")
+	t.Setenv("SENTINELS_SEED", "42")
+	t.Setenv("SENTINELS_EXPANSIONS", "baseset,bogus")
+	t.Setenv("SENTINELS_ADDR", ":9999")
+	c := ConfigFromEnv()
+	if c.Players != 3 || c.Seed != 42 || len(c.Expansions) != 1 || c.Addr != ":9999" {
+		t.Errorf("got players %d, seed %d, expansions %v, addr %q", c.Players, c.Seed, c.Expansions, c.Addr)
+	}
+}
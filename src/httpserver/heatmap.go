@@ -0,0 +1,72 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// Sizes of the heat map's SVG, in pixels.
+const (
+	heatCell   = 28  // side of a cell
+	heatLabels = 180 // room for the villain and environment names
+)
+
+// heatMap sends the heat map of the villains and environments in the
+// request's collection for "pc" heroes, or the form's default number, as
+// JSON or, with "format=svg", as an SVG image.  "adv" scores villains in
+// advanced mode.
+func (sv *server) heatMap(w http.ResponseWriter, r *http.Request) {
+	cs, err := sv.requestCards(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	pc := sv.config.Players
+	if v := r.FormValue("pc"); v != "" {
+		if pc, err = strconv.Atoi(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	hm, err := cs.HeatMap(pc, r.FormValue("adv") != "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.FormValue("format") == "svg" {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		writeHeatMap(w, hm)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hm)
+}
+
+// writeHeatMap draws a heat map as SVG: a row per villain and a column per
+// environment, each cell shaded from green for an easy game to red for a
+// hard one.
+func writeHeatMap(w io.Writer, hm *sentinels.HeatMap) {
+	width := heatLabels + heatCell*len(hm.Environments)
+	height := heatLabels + heatCell*len(hm.Villains)
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="Roboto, sans-serif" font-size="12">`+"\n", width, height)
+	for j, e := range hm.Environments {
+		x := heatLabels + heatCell*j + heatCell/2
+		fmt.Fprintf(w, `<text transform="translate(%d %d) rotate(-60)">%s</text>`+"\n", x, heatLabels-4, html.EscapeString(e))
+	}
+	for i, v := range hm.Villains {
+		y := heatLabels + heatCell*i
+		fmt.Fprintf(w, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", heatLabels-4, y+heatCell*2/3, html.EscapeString(v))
+		for j, e := range hm.Environments {
+			lp := hm.Loss[i][j]
+			fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" fill="hsl(%d, 70%%, 50%%)"><title>%s in %s: %d (%d%% loss)</title></rect>`+"\n",
+				heatLabels+heatCell*j, y, heatCell, heatCell, 120*(100-lp)/100, html.EscapeString(v), html.EscapeString(e), hm.Totals[i][j], lp)
+		}
+	}
+	fmt.Fprintln(w, "</svg>")
+}
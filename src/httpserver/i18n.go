@@ -0,0 +1,64 @@
+package httpserver
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// requestLang returns the language to show a page in: the "lang" setting,
+// if the request has one, even if it is empty for English, and otherwise
+// the language the browser prefers most.
+func requestLang(r *http.Request) string {
+	r.ParseForm()
+	if v, ok := r.Form["lang"]; ok {
+		return v[0]
+	}
+	return acceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+// acceptLanguage returns the most preferred language in an Accept-Language
+// header, or "" if there is none.
+func acceptLanguage(header string) string {
+	type pref struct {
+		lang string
+		q    float64
+	}
+	var prefs []pref
+	for _, f := range strings.Split(header, ",") {
+		p := pref{q: 1}
+		parts := strings.Split(f, ";")
+		p.lang = strings.TrimSpace(parts[0])
+		for _, param := range parts[1:] {
+			if v := strings.TrimSpace(param); strings.HasPrefix(v, "q=") {
+				if q, err := strconv.ParseFloat(v[2:], 64); err == nil {
+					p.q = q
+				}
+			}
+		}
+		if p.lang != "" && p.lang != "*" && p.q > 0 {
+			prefs = append(prefs, p)
+		}
+	}
+	if len(prefs) == 0 {
+		return ""
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+	return prefs[0].lang
+}
+
+// percent formats a fraction as a whole percentage in the given language.
+func percent(lang string, f float64) string {
+	return sentinels.FormatPercent(lang, int(math.Round(f*100)))
+}
+
+// describe describes a setup in the given language; see Setup.Describe.
+func describe(s *sentinels.Setup, lang string) string {
+	c := *s
+	c.Lang = lang
+	return c.Describe()
+}
@@ -0,0 +1,181 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// Page sizes of the history page and API.
+const (
+	historyPageSize = 50
+	maxHistoryLimit = 1000
+)
+
+// houseRule is a house rule offered on the result page, checked if the
+// setup's notes include it.
+type houseRule struct {
+	Rule    string
+	Checked bool
+}
+
+// houseRules returns the common house rules, checking those in chosen,
+// followed by any others chosen.
+func houseRules(chosen []string) []houseRule {
+	var hr []houseRule
+	for _, r := range sentinels.HouseRules {
+		hr = append(hr, houseRule{r, contains(chosen, r)})
+	}
+	for _, r := range chosen {
+		if !contains(sentinels.HouseRules, r) {
+			hr = append(hr, houseRule{r, true})
+		}
+	}
+	return hr
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// saveNotes attaches the posted notes and house rules to a setup and its
+// history entry, then shows the setup again if it is the session's current
+// one, or else the history.
+func (sv *server) saveNotes(w http.ResponseWriter, r *http.Request, token string) {
+	res := newResult(w, r)
+	text, rules := r.FormValue("text"), r.Form["rule"]
+	if s := res.sess.current(); s != nil && s.Token == token {
+		if err := s.Annotate(text, rules); err != nil {
+			res.Msg = err.Error()
+			sv.render(w, "result.html", res)
+			return
+		}
+		sv.display(w, res, s, "")
+		return
+	}
+	if err := sentinels.DefaultHistory.Annotate(token, text, rules); err != nil {
+		res.Msg = err.Error()
+		sv.render(w, "result.html", res)
+		return
+	}
+	http.Redirect(w, r, "/history", http.StatusSeeOther)
+}
+
+// historyPage is the data for the history template.
+type historyPage struct {
+	Entries []*sentinels.HistoryEntry
+	Total   int
+	Query   url.Values   // the filters, to fill in the form
+	Next    template.URL // the next page, if there is one
+	Msg     string
+	Lang    string
+}
+
+// history lists the most recent setups with their results and notes,
+// filtered as in historyQuery.
+func (sv *server) history(w http.ResponseWriter, r *http.Request) {
+	page := &historyPage{Query: r.URL.Query(), Lang: requestLang(r)}
+	q, err := historyQuery(r, historyPageSize)
+	if err != nil {
+		page.Msg = err.Error()
+		sv.render(w, "history.html", page)
+		return
+	}
+	page.Entries, page.Total = sentinels.DefaultHistory.Search(q)
+	if q.Offset+len(page.Entries) < page.Total {
+		next := r.URL.Query()
+		next.Set("offset", strconv.Itoa(q.Offset+len(page.Entries)))
+		page.Next = template.URL("/history?" + next.Encode())
+	}
+	sv.render(w, "history.html", page)
+}
+
+// historyResult is the answer of the history API.
+type historyResult struct {
+	Total   int // matches, of which Entries is the requested page
+	Entries []*sentinels.HistoryEntry
+}
+
+// historyAPI sends the history entries matching the query as JSON.
+func historyAPI(w http.ResponseWriter, r *http.Request) {
+	q, err := historyQuery(r, maxHistoryLimit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var res historyResult
+	res.Entries, res.Total = sentinels.DefaultHistory.Search(q)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&res)
+}
+
+// historyQuery reads the history filters from a request: "villain",
+// "hero", "env" and "group" names; "from" and "to" dates (YYYY-MM-DD,
+// both inclusive); "result" ("won", "lost" or "unplayed"); "minlp" and
+// "maxlp" loss percentages; "vetoed" to include vetoed setups; and
+// "offset" and "limit", which defaults to and may not exceed max.
+func historyQuery(r *http.Request, max int) (*sentinels.HistoryQuery, error) {
+	q := &sentinels.HistoryQuery{
+		Villain:     r.FormValue("villain"),
+		Hero:        r.FormValue("hero"),
+		Environment: r.FormValue("env"),
+		Group:       r.FormValue("group"),
+		Vetoed:      r.FormValue("vetoed") != "",
+		Limit:       max,
+	}
+	switch q.Result = r.FormValue("result"); q.Result {
+	case "", sentinels.Won, sentinels.Lost, sentinels.Unplayed:
+	default:
+		return nil, fmt.Errorf("result must be %q, %q or %q, not %q.", sentinels.Won, sentinels.Lost, sentinels.Unplayed, q.Result)
+	}
+	for _, f := range []struct {
+		name    string
+		t       *time.Time
+		nextDay bool
+	}{{"from", &q.From, false}, {"to", &q.To, true}} {
+		v := r.FormValue(f.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a date such as 2014-06-30, not %q.", f.name, v)
+		}
+		if f.nextDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		*f.t = t
+	}
+	for _, f := range []struct {
+		name     string
+		v        *int
+		min, max int
+	}{
+		{"minlp", &q.MinLoss, 0, 100},
+		{"maxlp", &q.MaxLoss, 0, 100},
+		{"offset", &q.Offset, 0, 1 << 30},
+		{"limit", &q.Limit, 1, max},
+	} {
+		s := r.FormValue(f.name)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < f.min || n > f.max {
+			return nil, fmt.Errorf("%s must be %d to %d, not %q.", f.name, f.min, f.max, s)
+		}
+		*f.v = n
+	}
+	return q, nil
+}
@@ -0,0 +1,42 @@
+package httpserver
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// overlayRefresh is how often, in seconds, the overlay reloads by default.
+const overlayRefresh = 5
+
+// overlayPage is the data for the overlay template.
+type overlayPage struct {
+	Setup   *sentinels.Setup
+	Lang    string
+	Theme   string // "dark", "light" or "chroma"
+	Refresh int
+	Msg     string
+}
+
+// overlay shows the current setup of the table in the "table" parameter in
+// large type, reloading every few seconds, for use as a browser source when
+// streaming a game.
+func (sv *server) overlay(w http.ResponseWriter, r *http.Request) {
+	p := &overlayPage{Lang: requestLang(r), Theme: r.FormValue("theme"), Refresh: overlayRefresh}
+	switch p.Theme {
+	case "dark", "light", "chroma":
+	default:
+		p.Theme = "dark"
+	}
+	if n, err := strconv.Atoi(r.FormValue("refresh")); err == nil && n > 0 {
+		p.Refresh = n
+	}
+	if ss := overlaySession(r.FormValue("table")); ss == nil {
+		p.Msg = "No table with that key."
+	} else if p.Setup = ss.current(); p.Setup == nil {
+		p.Msg = "Waiting for a setup."
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	sv.render(w, "overlay.html", p)
+}
@@ -0,0 +1,93 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// searchDone is the last event of a search stream.
+type searchDone struct {
+	Setup      *sentinels.Setup
+	Iterations int
+	URL        string // page showing the setup
+}
+
+// searchEvents runs the search posted by the form and streams its progress
+// as Server-Sent Events, so that the page can show how a search for a
+// narrow target is going.  "progress" events carry a sentinels.Progress
+// every sentinels.ProgressInterval iterations; the stream ends with a
+// "setup" event, after which the setup is the session's current one, or an
+// "error" event.  The search stops if the client goes away.
+func (sv *server) searchEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming isn't supported.", http.StatusInternalServerError)
+		return
+	}
+	res := newResult(w, r)
+	p, err := sv.formParams(r, res.Players)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	progress := make(chan sentinels.Progress, 1)
+	p.Progress = func(pr sentinels.Progress) {
+		// if the client is behind, replace the report it hasn't had.
+		select {
+		case <-progress:
+		default:
+		}
+		progress <- pr
+	}
+	done := make(chan *searchDone, 1)
+	errc := make(chan error, 1)
+	go func() {
+		s, i, err := sv.findCached(r.Context(), p, r.FormValue("fresh") == "on")
+		if err != nil {
+			errc <- err
+			return
+		}
+		done <- &searchDone{Setup: s, Iterations: i, URL: "/setup"}
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case pr := <-progress:
+			writeEvent(w, "progress", pr)
+		case d := <-done:
+			res.sess.push(d.Setup)
+			writeEvent(w, "setup", d)
+			flusher.Flush()
+			return
+		case err := <-errc:
+			writeEvent(w, "error", err.Error())
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes a Server-Sent Event whose data is v as JSON.
+func writeEvent(w http.ResponseWriter, event string, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(err.Error())
+		event = "error"
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b)
+}
+
+// currentSetup shows the session's current setup, e.g. once a streamed
+// search has found it.
+func (sv *server) currentSetup(w http.ResponseWriter, r *http.Request) {
+	res := newResult(w, r)
+	sv.display(w, res, res.sess.current(), "No setup yet.")
+}
@@ -0,0 +1,138 @@
+package httpserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// quizTries is how many targets the quiz tries before giving up on finding
+// a setup in the collection.
+const quizTries = 5
+
+// quizClose is how near a guess must be to the prediction to count as close.
+const quizClose = 5
+
+// quizPage is the data for the quiz template.
+type quizPage struct {
+	Setup    *sentinels.Setup
+	Guess    int
+	Off      int  // distance of the guess from the prediction
+	Revealed bool // whether Setup is the answer to Guess
+	Score    sentinels.QuizScore
+	Close    int
+	Exp      string // the collection asked for, to keep for the next setup
+	Player   string // the player keeping score, if any
+	Msg      string
+	Lang     string
+}
+
+// quiz shows a random setup from the collection and asks for a guess at its
+// loss percentage, then reveals the prediction and keeps score.  The score
+// is saved with the player named in "player", or else kept for the
+// session.  The collection is the expansions in "exp", or else the form's
+// default ones.
+func (sv *server) quiz(w http.ResponseWriter, r *http.Request) {
+	ss := getSession(w, r)
+	page := &quizPage{Close: quizClose, Exp: r.FormValue("exp"), Lang: requestLang(r), Player: strings.TrimSpace(r.FormValue("player"))}
+	if r.Method == "POST" {
+		g, err := strconv.Atoi(r.FormValue("guess"))
+		if s := ss.quizSetup(); s == nil {
+			page.Msg = "That setup has expired; here's another."
+		} else if err != nil || g < 0 || g > 100 {
+			page.Msg = "Guess a loss percentage from 0 to 100."
+			page.Setup = s
+		} else {
+			page.Setup, page.Guess, page.Revealed = s, g, true
+			page.Off = ss.guessQuiz(g, page.Player == "")
+			if page.Player != "" {
+				if _, err := sv.profiles.AddQuizGuess(page.Player, page.Off, page.Off <= quizClose); err != nil {
+					page.Msg = err.Error()
+				}
+			}
+		}
+	}
+	if page.Setup == nil {
+		s, err := sv.quizSetup(r)
+		if err != nil {
+			page.Msg = err.Error()
+		} else {
+			ss.setQuiz(s)
+			page.Setup = s
+		}
+	}
+	if page.Player != "" {
+		page.Score = sv.profiles.QuizScore(page.Player)
+	} else {
+		page.Score = ss.quizScore()
+	}
+	sv.render(w, "quiz.html", page)
+}
+
+// quizSetup samples a setup for a random number of heroes and a random
+// target, so that the quiz covers easy and hard setups alike.  It isn't
+// added to the history.
+func (sv *server) quizSetup(r *http.Request) (*sentinels.Setup, error) {
+	exp := sv.config.Expansions
+	if v := r.FormValue("exp"); v != "" {
+		var err error
+		if exp, err = sentinels.ParseExpansions(v); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	for i := 0; i < quizTries; i++ {
+		seed := sentinels.NewSeed()
+		p := &sentinels.Params{
+			Players:     3 + int(uint64(seed)%3),
+			LossPercent: 5 + int(uint64(seed)>>8%91),
+			Range:       10,
+			Expansions:  exp,
+		}
+		var s *sentinels.Setup
+		if s, err = sv.engine.Sample(p, seed); err == nil {
+			return s, nil
+		}
+	}
+	return nil, err
+}
+
+// setQuiz makes s the setup the session's next guess is for.
+func (ss *session) setQuiz(s *sentinels.Setup) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.quiz = s
+}
+
+// quizSetup returns the setup awaiting a guess, or nil if there is none.
+func (ss *session) quizSetup() *sentinels.Setup {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.quiz
+}
+
+// guessQuiz scores a guess at the pending setup's loss percentage, which
+// can't then be guessed again, and returns its distance from the
+// prediction.  The guess counts toward the session's score if keep is set.
+func (ss *session) guessQuiz(guess int, keep bool) int {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	off := guess - ss.quiz.LossPercent
+	if off < 0 {
+		off = -off
+	}
+	ss.quiz = nil
+	if keep {
+		ss.score.Add(off, off <= quizClose)
+	}
+	return off
+}
+
+// quizScore returns the session's quiz score.
+func (ss *session) quizScore() sentinels.QuizScore {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.score
+}
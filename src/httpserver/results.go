@@ -0,0 +1,122 @@
+package httpserver
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// Bounds on the cache of recent search results.
+const (
+	resultCacheSize = 64
+	resultCacheTTL  = 2 * time.Minute
+)
+
+// resultCache holds the most recently used search results, so that a
+// repeated request, such as the form being resubmitted, gets a copy of the
+// setup already found instead of searching again.  Each copy is reissued
+// with its own token, so that vetoing or playing it affects no one else's.
+type resultCache struct {
+	mu sync.Mutex
+	ll *list.List // of *cachedResult, most recently used first
+	m  map[string]*list.Element
+}
+
+// cachedResult is a search result and the parameters it was found for.
+type cachedResult struct {
+	key    string
+	setup  *sentinels.Setup
+	iter   int
+	time   time.Time
+	tokens []string // of the setup and the copies of it issued
+}
+
+func newResultCache() *resultCache {
+	return &resultCache{ll: list.New(), m: make(map[string]*list.Element)}
+}
+
+// resultKey returns the key of the results for p, or "" if they can't be
+// cached.  The key includes the data version, so that reloading the data
+// makes earlier results stale.
+func resultKey(p *sentinels.Params) string {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return ""
+	}
+	return sentinels.DataVersion() + " " + string(b)
+}
+
+// get returns the cached result for key, if it is recent and neither its
+// setup nor a copy of it has since been vetoed or played.
+func (c *resultCache) get(key string) (*sentinels.Setup, int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.m[key]
+	if !ok {
+		return nil, 0, false
+	}
+	cr := el.Value.(*cachedResult)
+	stale := time.Since(cr.time) > resultCacheTTL
+	for _, t := range cr.tokens {
+		if e := sentinels.DefaultHistory.Entry(t); e != nil && (e.Vetoed || e.Result != "") {
+			stale = true
+		}
+	}
+	if stale {
+		c.ll.Remove(el)
+		delete(c.m, key)
+		return nil, 0, false
+	}
+	c.ll.MoveToFront(el)
+	return cr.setup, cr.iter, true
+}
+
+// put caches a copy of a result before it is shown, so that later changes
+// to the setup, such as notes, aren't copied to others.  The least recently
+// used result is evicted if the cache is full.
+func (c *resultCache) put(key string, s *sentinels.Setup, i int) {
+	cp := *s
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.m[key]; ok {
+		c.ll.Remove(el)
+	}
+	c.m[key] = c.ll.PushFront(&cachedResult{key, &cp, i, time.Now(), []string{s.Token}})
+	for c.ll.Len() > resultCacheSize {
+		el := c.ll.Back()
+		c.ll.Remove(el)
+		delete(c.m, el.Value.(*cachedResult).key)
+	}
+}
+
+// issued notes the token of a copy of the result for key.
+func (c *resultCache) issued(key, token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.m[key]; ok {
+		cr := el.Value.(*cachedResult)
+		cr.tokens = append(cr.tokens, token)
+	}
+}
+
+// findCached runs a search, reusing a recent result for the same
+// parameters unless fresh is set.  The search gives up if ctx is done.
+func (sv *server) findCached(ctx context.Context, p *sentinels.Params, fresh bool) (*sentinels.Setup, int, error) {
+	key := resultKey(p)
+	if key != "" && !fresh {
+		if s, i, ok := sv.results.get(key); ok {
+			s = sv.engine.Reissue(s)
+			sv.results.issued(key, s.Token)
+			return s, i, nil
+		}
+	}
+	s, i, err := sv.engine.Find(ctx, p)
+	if err == nil && key != "" {
+		sv.results.put(key, s, i)
+	}
+	return s, i, err
+}
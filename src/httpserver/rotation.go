@@ -0,0 +1,57 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// rotationInfo describes one of a group's rotations for the rotation API.
+type rotationInfo struct {
+	Type  string // "hero", "villain" or "environment"
+	On    bool
+	Left  []string
+	Drawn []string
+}
+
+// rotationAPI sends the state of the rotations of the profile in "group",
+// for the profile's collection or else the form's default one, as JSON.  A
+// POST with "reset" set to a type, or "all", first refills that bag.
+func (sv *server) rotationAPI(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("group")
+	if sv.profiles.Get(name) == nil {
+		http.Error(w, fmt.Sprintf("No profile named %q.", name), http.StatusNotFound)
+		return
+	}
+	if r.Method == "POST" {
+		reset := r.FormValue("reset")
+		found := false
+		for t, n := range cardTypeNames {
+			if reset == n || reset == "all" {
+				found = true
+				if err := sv.profiles.ResetBag(name, t); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			}
+		}
+		if !found {
+			http.Error(w, fmt.Sprintf("reset must be hero, villain, environment or all, not %q.", reset), http.StatusBadRequest)
+			return
+		}
+	}
+	pr := sv.profiles.Get(name)
+	p := &sentinels.Params{Expansions: sv.config.Expansions}
+	pr.Apply(p)
+	p.Bag = nil
+	cs := p.CardSet()
+	var rs []*rotationInfo
+	for _, t := range []sentinels.CardType{sentinels.Hero, sentinels.Villain, sentinels.Environment} {
+		b := pr.Bag(t, cs)
+		rs = append(rs, &rotationInfo{cardTypeNames[t], b.On, b.Left, b.Drawn})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rs)
+}
@@ -0,0 +1,995 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/uhhhclem/sentinels"
+	"github.com/uhhhclem/sentinels/bots/scheduler"
+	"github.com/uhhhclem/sentinels/bots/webhook"
+	"github.com/uhhhclem/sentinels/config"
+)
+
+type result struct {
+	PC         int
+	LP         int
+	RG         int
+	Promo      bool
+	Setup      *sentinels.Setup
+	Msg        string
+	Nump       string
+	Iterations int
+	Lang       string
+	Players    string // comma-separated names to seat, if any
+	Easier     []sentinels.Swap
+	Harder     []sentinels.Swap
+	CanUndo    bool
+	CanRedo    bool
+	ShareURL   template.URL // link to the setup, and to its QR code
+	QRURL      template.URL
+	OverlayURL string // stream overlay showing the session's current setup
+	CompareURL string // comparison with the previous setup, if any
+	HouseRules []houseRule
+	sess       *session
+}
+
+// newResult starts a result page for the request's language, players and
+// session.
+func newResult(w http.ResponseWriter, r *http.Request) *result {
+	return &result{Lang: requestLang(r), Players: r.FormValue("players"), sess: getSession(w, r)}
+}
+
+// formExpansion is an expansion and the value the form posts for it.
+type formExpansion struct {
+	Value string
+	Exp   sentinels.ExpansionType
+}
+
+// Title returns the expansion's display name.
+func (fe formExpansion) Title() string {
+	return fe.Exp.Title()
+}
+
+// formExpansions are the expansions the form offers.  Naming them here,
+// rather than by position, lets the form and sentinels.ExpansionType change
+// independently.
+var formExpansions = []formExpansion{
+	{"baseset", sentinels.BaseSet},
+	{"miniexpansion", sentinels.MiniExpansion},
+	{"rookcity", sentinels.RookCity},
+	{"infernalrelics", sentinels.InfernalRelics},
+	{"shatteredtimelines", sentinels.ShatteredTimelines},
+	{"vengeance", sentinels.Vengeance},
+	{"promos", sentinels.Promos},
+}
+
+// formExpansionValue returns the expansion the form value v stands for.
+func formExpansionValue(v string) (sentinels.ExpansionType, error) {
+	for _, fe := range formExpansions {
+		if fe.Value == v {
+			return fe.Exp, nil
+		}
+	}
+	return 0, fmt.Errorf("Unknown expansion %q.", v)
+}
+
+// selectedExpansions returns the expansions checked in the form's "exp"
+// values or, if "through" is set, those released up to it, plus promos if
+// they are checked.
+func selectedExpansions(r *http.Request) ([]sentinels.ExpansionType, error) {
+	exp := []sentinels.ExpansionType{}
+	promos := false
+	for _, v := range r.Form["exp"] {
+		e, err := formExpansionValue(v)
+		if err != nil {
+			return nil, err
+		}
+		exp = append(exp, e)
+		promos = promos || e == sentinels.Promos
+	}
+	if v := r.FormValue("through"); v != "" {
+		e, err := formExpansionValue(v)
+		if err != nil {
+			return nil, err
+		}
+		exp = sentinels.OwnedThrough(e)
+		if promos {
+			exp = append(exp, sentinels.Promos)
+		}
+	}
+	return exp, nil
+}
+
+// mergeCollections combines the expansions selected in the form with a
+// second collection, checked in its "exp2" values, for friends pooling their
+// boxes.  With mode "union" every expansion either owns is used, and a base
+// deck both own can be played twice; with "intersect" only the expansions
+// both own are.
+func mergeCollections(r *http.Request, exp []sentinels.ExpansionType, mode string) ([]sentinels.ExpansionType, map[sentinels.ExpansionType]int, error) {
+	var other []sentinels.ExpansionType
+	for _, v := range r.Form["exp2"] {
+		e, err := formExpansionValue(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		other = append(other, e)
+	}
+	both := func(e sentinels.ExpansionType) bool {
+		for _, x := range other {
+			if x == e {
+				return true
+			}
+		}
+		return false
+	}
+	switch mode {
+	case "union":
+		copies := make(map[sentinels.ExpansionType]int)
+		merged := append([]sentinels.ExpansionType{}, other...)
+		for _, e := range exp {
+			if !both(e) {
+				merged = append(merged, e)
+			} else if e != sentinels.Promos {
+				copies[e] = 2
+			}
+		}
+		return merged, copies, nil
+	case "intersect":
+		var merged []sentinels.ExpansionType
+		for _, e := range exp {
+			if both(e) {
+				merged = append(merged, e)
+			}
+		}
+		return merged, nil, nil
+	}
+	return nil, nil, fmt.Errorf("Unknown way to merge collections %q.", mode)
+}
+
+// formChoice is an expansion on the form, checked if it is a default.
+type formChoice struct {
+	formExpansion
+	Checked bool
+}
+
+// formPage is the data for the form template.
+type formPage struct {
+	Presets    []string
+	Groups     []string // profiles a setup can be recorded for
+	Pools      []string
+	Players    int // default number of heroes
+	Expansions []formChoice
+	Promos     []string // promo cards, for collections with only some
+}
+
+// draftChoices is the number of heroes offered to each player in a draft.
+const draftChoices = 3
+
+func (sv *server) handler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		fp := &formPage{
+			Presets: sv.profiles.PresetNames(),
+			Groups:  sv.profiles.Names(),
+			Pools:   sentinels.EnvironmentPools(),
+			Players: sv.config.Players,
+		}
+		promos := sentinels.Select(sentinels.WithExpansions(sentinels.Promos))
+		for _, l := range [][]*sentinels.Card{promos.Heroes, promos.Villains, promos.Environments} {
+			for _, c := range l {
+				fp.Promos = append(fp.Promos, c.Name)
+			}
+		}
+		sort.Strings(fp.Promos)
+		for _, fe := range formExpansions {
+			fc := formChoice{formExpansion: fe}
+			for _, e := range sv.config.Expansions {
+				fc.Checked = fc.Checked || e == fe.Exp
+			}
+			fp.Expansions = append(fp.Expansions, fc)
+		}
+		sv.render(w, "form.html", fp)
+	case "POST":
+		if token := r.FormValue("veto"); token != "" {
+			sv.veto(w, r, token)
+		} else if token := r.FormValue("reroll"); token != "" {
+			sv.reroll(w, r, token)
+		} else if r.FormValue("undo") != "" {
+			res := newResult(w, r)
+			sv.display(w, res, res.sess.back(), "Nothing to undo.")
+		} else if r.FormValue("redo") != "" {
+			res := newResult(w, r)
+			sv.display(w, res, res.sess.forward(), "Nothing to redo.")
+		} else if token := r.FormValue("played"); token != "" {
+			sv.recordResult(w, r, token)
+		} else if token := r.FormValue("notes"); token != "" {
+			sv.saveNotes(w, r, token)
+		} else if r.FormValue("drafted") != "" {
+			sv.finishDraft(w, r)
+		} else if name := r.FormValue("preset"); name != "" {
+			res := newResult(w, r)
+			if p := sv.profiles.Preset(name); p == nil {
+				res.Msg = fmt.Sprintf("No preset named %q.", name)
+				sv.render(w, "result.html", res)
+			} else {
+				p.Team = sv.profiles.Team(strings.Split(res.Players, ","))
+				sv.groupParams(p, r)
+				sv.find(r.Context(), w, res, p, r.FormValue("fresh") == "on")
+			}
+		} else {
+			res := newResult(w, r)
+			p, err := sv.formParams(r, res.Players)
+			if err != nil {
+				res.Msg = err.Error()
+				if _, ok := err.(badRequest); ok {
+					w.WriteHeader(http.StatusBadRequest)
+				}
+				sv.render(w, "result.html", res)
+				return
+			}
+			if name := r.FormValue("savepreset"); name != "" {
+				if err := sv.profiles.SavePreset(name, p); err != nil {
+					log.Println(err)
+				}
+			}
+			if r.FormValue("draft") == "on" {
+				sv.deal(w, res, p)
+				return
+			}
+			sv.find(r.Context(), w, res, p, r.FormValue("fresh") == "on")
+		}
+	default:
+		log.Printf("Unhandled method: %s", r.Method)
+	}
+}
+
+// badRequest is an error in a posted form, which the app answers with 400
+// Bad Request.
+type badRequest struct{ error }
+
+// formParams reads the search parameters posted by the form, seating the
+// named players.  Its errors are badRequests.
+func (sv *server) formParams(r *http.Request, players string) (*sentinels.Params, error) {
+	p, err := sv.readForm(r, players)
+	if err != nil {
+		return nil, badRequest{err}
+	}
+	return p, nil
+}
+
+// readForm does the work of formParams.
+func (sv *server) readForm(r *http.Request, players string) (*sentinels.Params, error) {
+	m, err := formInts(r, "pc", "lp")
+	if err != nil {
+		return nil, err
+	}
+	exp, err := selectedExpansions(r)
+	if err != nil {
+		return nil, err
+	}
+	var copies map[sentinels.ExpansionType]int
+	if mode := r.FormValue("merge"); mode != "" {
+		if exp, copies, err = mergeCollections(r, exp, mode); err != nil {
+			return nil, err
+		}
+	}
+	if len(exp) == 0 {
+		return nil, errors.New("No card set selected.")
+	}
+	p := &sentinels.Params{Players: m["pc"], LossPercent: m["lp"], Range: 10, Expansions: exp, Copies: copies}
+	if level := r.FormValue("level"); level != "" {
+		if p.LossPercent, err = sentinels.ParseLevel(level); err != nil {
+			return nil, err
+		}
+	}
+	if r.FormValue("family") == "on" {
+		p.ExcludeTags = sentinels.FamilyTags
+	}
+	p.Advanced = r.FormValue("advanced") == "on"
+	p.HighConfidence = r.FormValue("confident") == "on"
+	p.AllowConflicts = r.FormValue("conflicts") == "on"
+	if p.Tier, err = sentinels.ParseTier(r.FormValue("tier")); err != nil {
+		return nil, err
+	}
+	if p.Nemesis, err = sentinels.ParseNemesisMode(r.FormValue("nemesis")); err != nil {
+		return nil, err
+	}
+	if p.Style, err = sentinels.ParseStyle(r.FormValue("style")); err != nil {
+		return nil, err
+	}
+	if err := skipContent(r, p); err != nil {
+		return nil, err
+	}
+	for _, v := range r.Form["double"] {
+		e, err := formExpansionValue(v)
+		if err != nil {
+			return nil, err
+		}
+		if p.Copies == nil {
+			p.Copies = make(map[sentinels.ExpansionType]int)
+		}
+		p.Copies[e] = 2
+	}
+	if pool := r.FormValue("pool"); pool != "" {
+		p.Pools = []string{pool}
+	}
+	p.Team = sv.profiles.Team(strings.Split(players, ","))
+	p.Fair = r.FormValue("fair") == "on"
+	p.Teaching = r.FormValue("teaching") == "on"
+	p.Promos = r.Form["promo"]
+	sv.groupParams(p, r)
+	return p, nil
+}
+
+// groupParams sets the group of a search from the request and applies the
+// parts of the group's profile the form doesn't cover: its promos, unless
+// the form chose some, and its rotations.
+func (sv *server) groupParams(p *sentinels.Params, r *http.Request) {
+	p.Group = sv.group(r)
+	if pr := sv.profiles.Get(p.Group); pr != nil {
+		if len(p.Promos) == 0 {
+			p.Promos = pr.Promos
+		}
+		pr.Rotate(p)
+	}
+}
+
+// skipContent applies the form's "skip" values, such as "rookcity:heroes",
+// by leaving that part of the expansion out of the search.
+func skipContent(r *http.Request, p *sentinels.Params) error {
+	for _, v := range r.Form["skip"] {
+		f := strings.SplitN(v, ":", 2)
+		if len(f) != 2 {
+			return fmt.Errorf("Bad skip value %q.", v)
+		}
+		e, err := formExpansionValue(f[0])
+		if err != nil {
+			return err
+		}
+		var list *[]sentinels.ExpansionType
+		switch f[1] {
+		case "heroes":
+			list = &p.HeroExpansions
+		case "villains":
+			list = &p.VillainExpansions
+		case "environments":
+			list = &p.EnvExpansions
+		default:
+			return fmt.Errorf("Bad skip value %q.", v)
+		}
+		if *list == nil {
+			*list = append([]sentinels.ExpansionType{}, p.Expansions...)
+		}
+		kept := (*list)[:0]
+		for _, x := range *list {
+			if x != e {
+				kept = append(kept, x)
+			}
+		}
+		*list = kept
+	}
+	return nil
+}
+
+// find runs a search, or reuses a recent one unless fresh is set, and
+// renders the result page.  The search gives up if ctx is done.
+func (sv *server) find(ctx context.Context, w http.ResponseWriter, res *result, p *sentinels.Params, fresh bool) {
+	s, i, err := sv.findCached(ctx, p, fresh)
+	sv.show(w, res, p, s, i, err)
+}
+
+// show renders the result page for a search.
+func (sv *server) show(w http.ResponseWriter, res *result, p *sentinels.Params, s *sentinels.Setup, i int, err error) {
+	res.PC = p.Players
+	res.LP = p.LossPercent
+	res.Nump = fmt.Sprintf("%d heroes", p.Players)
+	res.Setup, res.Iterations = s, i
+	if err != nil {
+		res.Msg = err.Error()
+	} else {
+		res.sess.push(s)
+		res.annotate()
+	}
+	sv.render(w, "result.html", res)
+}
+
+// draftPage is the data for the draft template.
+type draftPage struct {
+	Offers  [][]*sentinels.Card
+	Token   string // the session's key for the search parameters
+	Lang    string
+	Players []string
+}
+
+// deal deals a hero draft and renders the page on which players pick.
+func (sv *server) deal(w http.ResponseWriter, res *result, p *sentinels.Params) {
+	d, err := sv.engine.DealDraft(p, draftChoices)
+	if err != nil {
+		res.Msg = err.Error()
+		sv.render(w, "result.html", res)
+		return
+	}
+	page := &draftPage{Offers: d.Offers, Token: res.sess.addDraft(p), Lang: res.Lang}
+	names := strings.Split(res.Players, ",")
+	for i := range d.Offers {
+		n := fmt.Sprintf("Player %d", i+1)
+		if i < len(names) && strings.TrimSpace(names[i]) != "" {
+			n = strings.TrimSpace(names[i])
+		}
+		page.Players = append(page.Players, n)
+	}
+	sv.render(w, "draft.html", page)
+}
+
+// finishDraft finds a setup for the heroes picked on the draft page, with
+// the parameters the session kept when the draft was dealt.
+func (sv *server) finishDraft(w http.ResponseWriter, r *http.Request) {
+	res := newResult(w, r)
+	p := res.sess.takeDraft(r.FormValue("drafted"))
+	if p == nil {
+		res.Msg = "That draft has expired; deal a new one."
+		sv.render(w, "result.html", res)
+		return
+	}
+	var picks []*sentinels.Card
+	for i := 0; i < p.Players; i++ {
+		if c, ok := sentinels.Lookup(r.FormValue(fmt.Sprintf("pick%d", i))); ok {
+			picks = append(picks, c)
+		}
+	}
+	s, i, err := sv.engine.FinishDraft(p, picks)
+	sv.show(w, res, p, s, i, err)
+}
+
+// veto replaces a vetoed setup with a new one.
+func (sv *server) veto(w http.ResponseWriter, r *http.Request, token string) {
+	res := newResult(w, r)
+	s, i, err := sv.engine.Veto(token)
+	if err != nil {
+		res.Msg = err.Error()
+		sv.render(w, "result.html", res)
+		return
+	}
+	res.sess.push(s)
+	res.Iterations = i
+	sv.display(w, res, s, "")
+}
+
+// reroll replaces the session's current setup with a new one at the same
+// target, keeping the heroes the players locked.
+func (sv *server) reroll(w http.ResponseWriter, r *http.Request, token string) {
+	res := newResult(w, r)
+	old := res.sess.current()
+	if old == nil || old.Token != token || old.Seed == nil {
+		res.Msg = "That setup can no longer be rerolled."
+		sv.render(w, "result.html", res)
+		return
+	}
+	r.ParseForm()
+	p := old.Seed.Params
+	p.Heroes = r.Form["lock"]
+	p.Seed = 0
+	s, i, err := sv.engine.Find(r.Context(), &p)
+	if err == nil {
+		sentinels.DefaultHistory.MarkVetoed(old.Token)
+	}
+	sv.show(w, res, &p, s, i, err)
+}
+
+// display renders the result page for s, or msg if s is nil.
+func (sv *server) display(w http.ResponseWriter, res *result, s *sentinels.Setup, msg string) {
+	if s == nil {
+		res.Msg = msg
+	} else {
+		res.Setup = s
+		res.PC = len(s.Heroes)
+		res.LP = s.LossPercent
+		res.Nump = fmt.Sprintf("%d heroes", res.PC)
+		res.annotate()
+	}
+	sv.render(w, "result.html", res)
+}
+
+// suggestionDelta is the difficulty change for the easier/harder suggestions.
+const suggestionDelta = 25
+
+// annotate assigns the named players to the setup's heroes, suggests swaps
+// to make the setup easier or harder, links to it and to its comparison with
+// the previous one, and notes what can be undone.
+func (res *result) annotate() {
+	res.CanUndo, res.CanRedo = res.sess.canUndo(), res.sess.canRedo()
+	q := res.Setup.ShareQuery()
+	res.ShareURL, res.QRURL = template.URL("/score?"+q), template.URL("/qr.png?"+q)
+	res.OverlayURL = "/overlay?table=" + res.sess.overlay
+	if prev := res.sess.previous(); prev != nil && res.sess.current() == res.Setup {
+		res.CompareURL = compareURL(prev, res.Setup)
+	}
+	res.HouseRules = houseRules(res.Setup.HouseRules)
+	res.Easier = res.Setup.Suggest(-suggestionDelta, 3)
+	res.Harder = res.Setup.Suggest(suggestionDelta, 3)
+	if res.Setup.Seating == nil && strings.TrimSpace(res.Players) != "" {
+		res.Setup.AssignSeats(strings.Split(res.Players, ","))
+	}
+}
+
+// recordResult records the outcome of a played setup and shows the stats.
+func (sv *server) recordResult(w http.ResponseWriter, r *http.Request, token string) {
+	if err := sentinels.DefaultHistory.RecordResult(token, r.FormValue("result") == sentinels.Won); err != nil {
+		sv.render(w, "result.html", &result{Msg: err.Error()})
+		return
+	}
+	http.Redirect(w, r, "/stats", http.StatusSeeOther)
+}
+
+// statsPage is the data for the stats template.
+type statsPage struct {
+	*sentinels.Stats
+	Lang string
+}
+
+// stats renders the pick and win rates of the cards in the history.
+func (sv *server) stats(w http.ResponseWriter, r *http.Request) {
+	sv.render(w, "stats.html", &statsPage{sentinels.DefaultHistory.Stats(), requestLang(r)})
+}
+
+// calendar sends the scheduled game nights, with the setups posted for
+// them, as an iCalendar feed to subscribe to.
+func (sv *server) calendar(w http.ResponseWriter, r *http.Request) {
+	if sv.scheduler == nil {
+		http.Error(w, "No schedule is configured.", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	if err := sv.scheduler.WriteCalendar(w, sentinels.Now()); err != nil {
+		log.Println(err)
+	}
+}
+
+// group returns the group in the request's "group" parameter, if it names
+// a profile.
+func (sv *server) group(r *http.Request) string {
+	if g := r.FormValue("group"); sv.profiles.Get(g) != nil {
+		return g
+	}
+	return ""
+}
+
+// leaderboardPage is the data for the leaderboard template.
+type leaderboardPage struct {
+	*sentinels.Leaderboard
+	Lang string
+}
+
+// leaderboard ranks the groups that have opted into the leaderboards.
+func (sv *server) leaderboard(w http.ResponseWriter, r *http.Request) {
+	sv.render(w, "leaderboard.html", &leaderboardPage{sentinels.DefaultHistory.Leaderboard(sv.profiles), requestLang(r)})
+}
+
+// leaderboardAPI responds with the leaderboards as JSON.
+func (sv *server) leaderboardAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sentinels.DefaultHistory.Leaderboard(sv.profiles))
+}
+
+// achievementsPage is the data for the achievements template.
+type achievementsPage struct {
+	Achievements []*sentinels.Achievement
+	Lang         string
+	Msg          string
+}
+
+// requestCards returns the collection a request covers: the expansions in
+// "exp", or else the form's default ones.
+func (sv *server) requestCards(r *http.Request) (*sentinels.CardSet, error) {
+	if v := r.FormValue("exp"); v != "" {
+		exp, err := sentinels.ParseExpansions(v)
+		if err != nil {
+			return nil, err
+		}
+		return sentinels.Select(sentinels.WithExpansions(exp...)), nil
+	}
+	return sentinels.Select(sentinels.WithExpansions(sv.config.Expansions...)), nil
+}
+
+// achievements renders the group's progress toward its achievements.
+func (sv *server) achievements(w http.ResponseWriter, r *http.Request) {
+	p := &achievementsPage{Lang: requestLang(r)}
+	if cs, err := sv.requestCards(r); err != nil {
+		p.Msg = err.Error()
+	} else {
+		p.Achievements = sentinels.DefaultHistory.Achievements(cs)
+	}
+	sv.render(w, "achievements.html", p)
+}
+
+// achievementsAPI responds with the group's achievements as JSON.
+func (sv *server) achievementsAPI(w http.ResponseWriter, r *http.Request) {
+	cs, err := sv.requestCards(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sentinels.DefaultHistory.Achievements(cs))
+}
+
+// statsAPI responds with the history's stats as JSON.
+func statsAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sentinels.DefaultHistory.Stats())
+}
+
+// whatIf responds with how buying the expansion in "buy" would change the
+// setups the expansions in "own" can make for "pc" heroes, as JSON.
+func whatIf(w http.ResponseWriter, r *http.Request) {
+	own, err := sentinels.ParseExpansions(r.FormValue("own"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	buy, err := sentinels.ParseExpansion(r.FormValue("buy"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	pc := 4
+	if v := r.FormValue("pc"); v != "" {
+		if pc, err = strconv.Atoi(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	pu, err := sentinels.WhatIf(own, buy, pc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pu)
+}
+
+// exportState sends the profiles, presets, history and custom cards as a
+// zip archive.
+func (sv *server) exportState(w http.ResponseWriter, r *http.Request) {
+	var custom []byte
+	if sv.config.Custom != "" {
+		b, err := readSource(sv.config.Custom)
+		if err != nil && !os.IsNotExist(err) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		custom = b
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="sentinels.zip"`)
+	if err := sentinels.Export(w, sv.profiles, sentinels.DefaultHistory, custom); err != nil {
+		log.Println(err)
+	}
+}
+
+// maxArchive is the size in bytes of the largest archive importState reads.
+const maxArchive = 32 << 20
+
+// importState replaces the profiles, presets, history and custom cards with
+// those in a posted archive.
+func (sv *server) importState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "POST an archive", http.StatusMethodNotAllowed)
+		return
+	}
+	b, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxArchive))
+	if err != nil {
+		code := http.StatusBadRequest
+		if _, ok := err.(*http.MaxBytesError); ok {
+			code = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	if err := sentinels.Import(bytes.NewReader(b), int64(len(b)), sv.profiles, sentinels.DefaultHistory, sv.restoreCustom(r)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateCards checks a posted card list and responds with diagnostics.
+func validateCards(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "POST a card list", http.StatusMethodNotAllowed)
+		return
+	}
+	dd := &sentinels.DifficultyData{}
+	if err := json.NewDecoder(r.Body).Decode(dd); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ds := sentinels.ValidateCards(dd)
+	valid := true
+	for _, d := range ds {
+		if d.Severity == "error" {
+			valid = false
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Valid       bool
+		Diagnostics []sentinels.Diagnostic
+	}{valid, ds})
+}
+
+// lintData sends the problems LintData finds with the posted full data or,
+// for a GET, the built-in data, as JSON.
+func lintData(w http.ResponseWriter, r *http.Request) {
+	var data []byte
+	if r.Method == "POST" {
+		var err error
+		if data, err = ioutil.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Diagnostics []sentinels.Diagnostic
+	}{sentinels.LintData(data)})
+}
+
+func formInts(r *http.Request, names ...string) (map[string]int, error) {
+	m := make(map[string]int)
+	for _, n := range names {
+		if i, err := strconv.Atoi(r.FormValue(n)); err != nil {
+			return nil, err
+		} else {
+			m[n] = i
+		}
+	}
+	return m, nil
+}
+
+// server holds what the handlers share.
+type server struct {
+	mu        sync.RWMutex // guards templates
+	templates *template.Template
+	profiles  *sentinels.Profiles
+	config    *Config
+	audit     *auditLog
+	engine    Engine
+	scheduler *scheduler.Scheduler // nil if no schedule is configured
+	results   *resultCache
+}
+
+// render executes the named template.
+func (sv *server) render(w http.ResponseWriter, name string, data interface{}) {
+	sv.mu.RLock()
+	t := sv.templates
+	sv.mu.RUnlock()
+	if err := t.ExecuteTemplate(w, name, data); err != nil {
+		log.Println(err)
+	}
+}
+
+// templateFiles are the templates the app renders.
+var templateFiles = []string{"form.html", "result.html", "draft.html", "stats.html", "overlay.html", "compare.html", "achievements.html", "leaderboard.html", "history.html", "dataedit.html", "quiz.html", "cards.html"}
+
+// parseTemplates reads the templates from the configured directory, or the
+// embedded ones if there is none.
+func (sv *server) parseTemplates() error {
+	t := template.New("").Funcs(template.FuncMap{
+		"spoken":       sentinels.SpokenPoints,
+		"percent":      percent,
+		"wholePercent": sentinels.FormatPercent,
+		"number":       sentinels.FormatInt,
+		"describe":     describe,
+	})
+	var err error
+	if sv.config.Templates == "" {
+		t, err = t.ParseFS(assets, templateFiles...)
+	} else {
+		var files []string
+		for _, f := range templateFiles {
+			files = append(files, filepath.Join(sv.config.Templates, f))
+		}
+		t, err = t.ParseFiles(files...)
+	}
+	if err != nil {
+		return err
+	}
+	sv.mu.Lock()
+	sv.templates = t
+	sv.mu.Unlock()
+	return nil
+}
+
+// Config configures the web app.
+type Config struct {
+	Templates string // directory holding the HTML templates; empty uses the embedded ones
+	Profiles  string // file of saved presets; empty keeps them in memory
+	History   string // file of generated setups; empty keeps it in memory
+	Addr      string // address for ListenAndServe
+	// Data and Custom are files of card data loaded in place of the
+	// built-in data, and of cards added to it; see sentinels.LoadData.
+	// Overlays are applied in order between them; see
+	// sentinels.LoadLayers.  Custom is the layer the data editor saves.
+	Data     string
+	Overlays []string
+	Custom   string
+	// RepairScale sorts and repairs the scale of the Data file when it is
+	// loaded rather than rejecting it; see sentinels.RepairData.
+	RepairScale bool
+	// AdminToken is the bearer token the admin routes require; they are
+	// disabled if it is empty.
+	AdminToken string
+	AuditLog   string // file to which admin changes are appended
+	// AuthToken and BasicAuth ("user:password"), if set, are required to
+	// use the app; see requireAuth.
+	AuthToken string
+	BasicAuth string
+	// Schedule is a scheduler configuration file whose jobs are run while
+	// the app is; see scheduler.LoadConfig.
+	Schedule string
+	// Webhooks are URLs sent every generated and played setup; see
+	// package webhook.
+	Webhooks []string
+	// Engine generates setups; nil means DefaultEngine.
+	Engine Engine
+	// Store, if set, keeps the profiles and history in place of the
+	// Profiles and History files.
+	Store sentinels.Store
+	// Players and Expansions are the form's defaults.
+	Players    int
+	Expansions []sentinels.ExpansionType
+	// Seed, if nonzero, runs the app deterministically, e.g. for end-to-end
+	// tests and demo recordings: random choices follow from it and
+	// timestamps start at sentinels.DeterministicEpoch.  Tokens and session
+	// keys are then predictable, so don't use it in production.
+	Seed int64
+}
+
+// ConfigFromFile reads the configuration from a file (see package config;
+// an empty path finds one as config.Load does) and then from the
+// environment, which overrides it.
+func ConfigFromFile(path string) (*Config, error) {
+	f, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{
+		Templates: f.Templates,
+		Profiles:  f.Storage.Profiles,
+		History:   f.Storage.History,
+		Addr:      f.Addr,
+		Data:      f.Data,
+		Overlays:  f.Overlays,
+		Custom:    f.Custom,
+		Players:   f.Players,
+	}
+	if c.Expansions, err = f.ExpansionTypes(); err != nil {
+		return nil, err
+	}
+	if f.Storage.Backend != "file" {
+		if c.Store, err = f.Storage.Open(); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.readEnv(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewHandler returns the web app as an http.Handler, for serving standalone
+// or from a serverless platform.  It loads the history into
+// sentinels.DefaultHistory, where the package records generated setups,
+// starts the scheduled jobs, if any, and posts events to the webhooks.
+func NewHandler(c *Config) (http.Handler, error) {
+	if c.Seed != 0 {
+		sentinels.Deterministic(c.Seed, sentinels.DeterministicEpoch)
+	}
+	sv := &server{config: c, audit: &auditLog{path: c.AuditLog}, engine: c.Engine, results: newResultCache()}
+	if sv.engine == nil {
+		sv.engine = DefaultEngine{}
+	}
+	if err := sv.parseTemplates(); err != nil {
+		return nil, err
+	}
+	if c.Data != "" || len(c.Overlays) > 0 || c.Custom != "" {
+		if _, err := sv.loadData(); err != nil {
+			return nil, err
+		}
+	} else if err := sentinels.EnsureData(); err != nil {
+		return nil, err
+	}
+	var err error
+	if c.Store != nil {
+		if sv.profiles, err = sentinels.OpenProfiles(c.Store); err != nil {
+			return nil, err
+		}
+		if sentinels.DefaultHistory, err = sentinels.OpenHistory(c.Store); err != nil {
+			return nil, err
+		}
+	} else if sv.profiles, err = sentinels.LoadProfiles(c.Profiles); err != nil {
+		return nil, err
+	} else if c.History != "" {
+		if sentinels.DefaultHistory, err = sentinels.LoadHistory(c.History); err != nil {
+			return nil, err
+		}
+	}
+	if c.Schedule != "" {
+		sc, err := scheduler.LoadConfig(c.Schedule)
+		if err != nil {
+			return nil, err
+		}
+		sv.scheduler = scheduler.New(sc, sv.profiles)
+		sv.scheduler.Start()
+	}
+	if len(c.Webhooks) > 0 {
+		(&webhook.Hooks{URLs: c.Webhooks}).Listen()
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", sv.handler)
+	mux.Handle("/css/", staticFiles)
+	mux.Handle("/svg/", staticFiles)
+	mux.HandleFunc("/api/validate", validateCards)
+	mux.HandleFunc("/api/lint", lintData)
+	mux.HandleFunc("/score", sv.shared)
+	mux.HandleFunc("/qr.png", qrCode)
+	mux.HandleFunc("/overlay", sv.overlay)
+	mux.HandleFunc("/compare", sv.compare)
+	mux.HandleFunc("/api/compare", compareAPI)
+	mux.HandleFunc("/stats", sv.stats)
+	mux.HandleFunc("/api/stats", statsAPI)
+	mux.HandleFunc("/history", sv.history)
+	mux.HandleFunc("/quiz", sv.quiz)
+	mux.HandleFunc("/api/history", historyAPI)
+	mux.HandleFunc("/achievements", sv.achievements)
+	mux.HandleFunc("/api/achievements", sv.achievementsAPI)
+	mux.HandleFunc("/calendar.ics", sv.calendar)
+	mux.HandleFunc("/leaderboard", sv.leaderboard)
+	mux.HandleFunc("/api/leaderboard", sv.leaderboardAPI)
+	mux.HandleFunc("/api/whatif", whatIf)
+	mux.HandleFunc("/api/heatmap", sv.heatMap)
+	mux.HandleFunc("/api/setups", sv.bulkSetups)
+	mux.HandleFunc("/api/search/events", sv.searchEvents)
+	mux.HandleFunc("/setup", sv.currentSetup)
+	mux.HandleFunc("/cards", sv.cards)
+	mux.HandleFunc("/api/cards", cardsAPI)
+	mux.HandleFunc("/api/expansions", expansionsAPI)
+	mux.HandleFunc("/api/bundles", bundlesAPI)
+	mux.HandleFunc("/api/rotation", sv.rotationAPI)
+	mux.HandleFunc("/api/export", sv.admin(sv.exportState))
+	mux.HandleFunc("/api/import", sv.admin(sv.importState))
+	mux.HandleFunc("/admin/reload/data", sv.admin(sv.reloadData))
+	mux.HandleFunc("/admin/reload/templates", sv.admin(sv.reloadTemplates))
+	mux.HandleFunc("/admin/audit", sv.admin(sv.showAudit))
+	mux.HandleFunc("/admin/data", sv.admin(sv.editData))
+	return requireAuth(c, mux), nil
+}
+
+// ListenAndServe serves the web app standalone, configured from the
+// configuration file, if there is one, and the environment.
+func ListenAndServe() error {
+	c, err := ConfigFromFile("")
+	if err != nil {
+		return err
+	}
+	h, err := NewHandler(c)
+	if err != nil {
+		return err
+	}
+	log.Printf("Listening on %s", c.Addr)
+	return http.ListenAndServe(c.Addr, h)
+}
@@ -0,0 +1,392 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/uhhhclem/sentinels"
+	"github.com/uhhhclem/sentinels/storage"
+)
+
+// fakeEngine returns a fixed setup, or err if it is set, and remembers the
+// parameters it was last asked for.
+type fakeEngine struct {
+	err    error
+	params *sentinels.Params
+}
+
+func (e *fakeEngine) setup() (*sentinels.Setup, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return sentinels.Score([]string{"Legacy", "Haka", "Tachyon"}, "Baron Blade", "Megalopolis", false)
+}
+
+func (e *fakeEngine) Find(ctx context.Context, p *sentinels.Params) (*sentinels.Setup, int, error) {
+	e.params = p
+	s, err := e.setup()
+	return s, 1, err
+}
+
+func (e *fakeEngine) Veto(token string) (*sentinels.Setup, int, error) {
+	s, err := e.setup()
+	return s, 1, err
+}
+
+func (e *fakeEngine) DealDraft(p *sentinels.Params, k int) (*sentinels.Draft, error) {
+	e.params = p
+	s, err := e.setup()
+	if err != nil {
+		return nil, err
+	}
+	d := &sentinels.Draft{}
+	for range s.Heroes {
+		d.Offers = append(d.Offers, s.Heroes)
+	}
+	return d, nil
+}
+
+func (e *fakeEngine) FinishDraft(p *sentinels.Params, picks []*sentinels.Card) (*sentinels.Setup, int, error) {
+	e.params = p
+	s, err := e.setup()
+	return s, 1, err
+}
+
+func (e *fakeEngine) Reissue(s *sentinels.Setup) *sentinels.Setup {
+	c := *s
+	c.Token = fmt.Sprintf("%s+", s.Token)
+	return &c
+}
+
+func (e *fakeEngine) Sample(p *sentinels.Params, seed int64) (*sentinels.Setup, error) {
+	e.params = p
+	return e.setup()
+}
+
+// newTestHandler returns the app configured with c, or the defaults if c is
+// nil.
+func newTestHandler(t *testing.T, c *Config) http.Handler {
+	if c == nil {
+		c = &Config{}
+	}
+	h, err := NewHandler(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// post submits the form to the app's front page.
+func post(h http.Handler, form url.Values) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestVetoUnknownToken(t *testing.T) {
+	h := newTestHandler(t, nil)
+	w := post(h, url.Values{"veto": {"nosuchtoken"}})
+	if !strings.Contains(w.Body.String(), "can&#39;t be vetoed") {
+		t.Errorf("failed veto page doesn't give the reason:\n%s", w.Body)
+	}
+}
+
+func TestExportImportNeedAdmin(t *testing.T) {
+	h := newTestHandler(t, &Config{AdminToken: "secret"})
+	for _, method := range []string{"GET", "POST"} {
+		for _, path := range []string{"/api/export", "/api/import"} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("%s %s without a token: status %d, want %d", method, path, w.Code, http.StatusUnauthorized)
+			}
+		}
+	}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/import", strings.NewReader(strings.Repeat("x", maxArchive+1)))
+	r.Header.Set("Authorization", "Bearer secret")
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized import: status %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestFormErrorsAreBadRequests(t *testing.T) {
+	h := newTestHandler(t, nil)
+	for _, form := range []url.Values{
+		{"pc": {"3"}, "lp": {"50"}, "exp": {"bogus"}},
+		{"pc": {"three"}, "lp": {"50"}, "exp": {"baseset"}},
+		{"pc": {"3"}, "lp": {"50"}, "exp": {"baseset"}, "tier": {"impossible"}},
+	} {
+		if w := post(h, form); w.Code != http.StatusBadRequest {
+			t.Errorf("%v: status %d, want %d", form, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestFormExpansionValue(t *testing.T) {
+	for _, fe := range formExpansions {
+		if e, err := formExpansionValue(fe.Value); err != nil || e != fe.Exp {
+			t.Errorf("formExpansionValue(%q) = %v, %v; want %v", fe.Value, e, err, fe.Exp)
+		}
+	}
+	for _, v := range []string{"", "bogus", "0", "Base Set"} {
+		if _, err := formExpansionValue(v); err == nil {
+			t.Errorf("formExpansionValue(%q) succeeded", v)
+		}
+	}
+}
+
+func TestSelectedExpansions(t *testing.T) {
+	for _, tc := range []struct {
+		form url.Values
+		want int // number of expansions, or -1 for an error
+	}{
+		{url.Values{"exp": {"baseset", "rookcity"}}, 2},
+		{url.Values{"exp": {"baseset", "bogus"}}, -1},
+		{url.Values{"through": {"rookcity"}}, 2},
+		{url.Values{"through": {"bogus"}}, -1},
+		{url.Values{}, 0},
+	} {
+		r := httptest.NewRequest("GET", "/?"+tc.form.Encode(), nil)
+		r.ParseForm()
+		exp, err := selectedExpansions(r)
+		switch {
+		case tc.want < 0 && err == nil:
+			t.Errorf("%v: selected %v, want an error", tc.form, exp)
+		case tc.want >= 0 && (err != nil || len(exp) != tc.want):
+			t.Errorf("%v: selected %v, %v; want %d expansions", tc.form, exp, err, tc.want)
+		}
+	}
+}
+
+func TestUnknownFormValues(t *testing.T) {
+	h := newTestHandler(t, nil)
+	for _, extra := range []url.Values{
+		{"exp": {"bogus"}},
+		{"skip": {"bogus:heroes"}},
+		{"skip": {"baseset:sidekicks"}},
+		{"skip": {"baseset"}},
+		{"double": {"bogus"}},
+	} {
+		form := url.Values{"pc": {"3"}, "lp": {"50"}, "exp": {"baseset"}}
+		for k, v := range extra {
+			form[k] = append(form[k], v...)
+		}
+		if w := post(h, form); w.Code != http.StatusBadRequest {
+			t.Errorf("%v: status %d, want %d", form, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetForm(t *testing.T) {
+	h := newTestHandler(t, &Config{Engine: &fakeEngine{}})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `name="exp"`) {
+		t.Errorf("GET /: status %d, body:\n%s", w.Code, w.Body)
+	}
+}
+
+func TestPostForm(t *testing.T) {
+	e := &fakeEngine{}
+	h := newTestHandler(t, &Config{Engine: e})
+	w := post(h, url.Values{"pc": {"4"}, "lp": {"30"}, "exp": {"baseset", "rookcity"}, "fresh": {"on"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d, want %d", w.Code, http.StatusOK)
+	}
+	if !strings.Contains(w.Body.String(), "Baron Blade") {
+		t.Errorf("result page doesn't show the engine's setup:\n%s", w.Body)
+	}
+	if p := e.params; p == nil || p.Players != 4 || p.LossPercent != 30 || len(p.Expansions) != 2 {
+		t.Errorf("engine was asked for %+v", p)
+	}
+
+	e.err = errors.New("No luck.")
+	w = post(h, url.Values{"pc": {"3"}, "lp": {"50"}, "exp": {"baseset"}, "fresh": {"on"}})
+	if !strings.Contains(w.Body.String(), "No luck.") {
+		t.Errorf("result page doesn't show the engine's error:\n%s", w.Body)
+	}
+}
+
+func TestPostInvalid(t *testing.T) {
+	e := &fakeEngine{}
+	h := newTestHandler(t, &Config{Engine: e})
+	for _, form := range []url.Values{
+		{"pc": {"3"}, "lp": {"fifty"}, "exp": {"baseset"}},
+		{"pc": {"3"}, "lp": {"50"}, "exp": {"baseset"}, "merge": {"sideways"}},
+		{"pc": {"3"}, "lp": {"50"}},
+	} {
+		if w := post(h, form); w.Code != http.StatusBadRequest {
+			t.Errorf("%v: status %d, want %d", form, w.Code, http.StatusBadRequest)
+		}
+	}
+	if e.params != nil {
+		t.Errorf("engine was asked for %+v after invalid input", e.params)
+	}
+}
+
+func TestNoExpansionsSelected(t *testing.T) {
+	h := newTestHandler(t, &Config{Engine: &fakeEngine{}})
+	w := post(h, url.Values{"pc": {"3"}, "lp": {"50"}})
+	if !strings.Contains(w.Body.String(), "No card set selected.") {
+		t.Errorf("page doesn't say no card set was selected:\n%s", w.Body)
+	}
+}
+
+func TestBulkUsesEngine(t *testing.T) {
+	e := &fakeEngine{}
+	h := newTestHandler(t, &Config{Engine: e})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/setups?count=2&exp=baseset", nil))
+	if w.Code != http.StatusOK || strings.Count(w.Body.String(), `"Villain":{"Name":"Baron Blade"`) != 2 {
+		t.Errorf("status %d, body:\n%s", w.Code, w.Body)
+	}
+	if e.params == nil {
+		t.Error("bulk setups didn't come from the engine")
+	}
+}
+
+func TestCardSearchIgnoresPunctuation(t *testing.T) {
+	h := newTestHandler(t, nil)
+	for _, q := range []string{"knyfe", "K.N.Y", "nyf"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/api/cards?q="+url.QueryEscape(q), nil))
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "K.N.Y.F.E.") {
+			t.Errorf("q=%s: status %d, body:\n%s", q, w.Code, w.Body)
+		}
+	}
+}
+
+func TestDraftParamsStayOnServer(t *testing.T) {
+	e := &fakeEngine{}
+	h := newTestHandler(t, &Config{Engine: e})
+	w := post(h, url.Values{"pc": {"3"}, "lp": {"50"}, "exp": {"baseset"}, "draft": {"on"}})
+	m := regexp.MustCompile(`name="drafted" value="([0-9a-f]+)"`).FindStringSubmatch(w.Body.String())
+	if m == nil {
+		t.Fatalf("draft page has no token:\n%s", w.Body)
+	}
+	cookie := w.Result().Cookies()[0]
+	finish := func(drafted string) *httptest.ResponseRecorder {
+		form := url.Values{"drafted": {drafted}, "pick0": {"Legacy"}, "pick1": {"Haka"}, "pick2": {"Tachyon"}}
+		r := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.AddCookie(cookie)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	e.params = nil
+	w = finish(`{"Players":3,"MaxIterations":2000000000}`)
+	if e.params != nil || !strings.Contains(w.Body.String(), "expired") {
+		t.Errorf("engine was asked for %+v with parameters from the client", e.params)
+	}
+	w = finish(m[1])
+	if p := e.params; p == nil || p.Players != 3 || p.MaxIterations == 2000000000 {
+		t.Errorf("engine was asked for %+v", p)
+	}
+	e.params = nil
+	if finish(m[1]); e.params != nil {
+		t.Error("a draft was finished twice")
+	}
+}
+
+// blockingEngine searches until its context is done, and then reports the
+// context's error on stopped.
+type blockingEngine struct {
+	*fakeEngine
+	stopped chan error
+}
+
+func (e *blockingEngine) Find(ctx context.Context, p *sentinels.Params) (*sentinels.Setup, int, error) {
+	<-ctx.Done()
+	e.stopped <- ctx.Err()
+	return nil, 0, ctx.Err()
+}
+
+func TestSearchEventsStopWithClient(t *testing.T) {
+	e := &blockingEngine{&fakeEngine{}, make(chan error, 1)}
+	h := newTestHandler(t, &Config{Engine: e})
+	ctx, cancel := context.WithCancel(context.Background())
+	form := url.Values{"pc": {"3"}, "lp": {"50"}, "exp": {"baseset"}}
+	r := httptest.NewRequest("GET", "/api/search/events?"+form.Encode(), nil).WithContext(ctx)
+	served := make(chan bool)
+	go func() {
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		close(served)
+	}()
+	cancel()
+	select {
+	case err := <-e.stopped:
+		if err != context.Canceled {
+			t.Errorf("search stopped with %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("search kept running after the client went away")
+	}
+	<-served
+}
+
+func TestCachedResultsGetTheirOwnTokens(t *testing.T) {
+	h := newTestHandler(t, nil)
+	form := url.Values{"pc": {"3"}, "lp": {"50"}, "exp": {"baseset"}}
+	token := regexp.MustCompile(`name="veto" value="([0-9a-f]+)"`)
+	var tokens []string
+	for i := 0; i < 2; i++ {
+		m := token.FindStringSubmatch(post(h, form).Body.String())
+		if m == nil {
+			t.Fatal("result page has no veto token")
+		}
+		tokens = append(tokens, m[1])
+	}
+	if tokens[0] == tokens[1] {
+		t.Fatalf("two visitors were given the same setup token %s", tokens[0])
+	}
+	if _, _, err := sentinels.Veto(tokens[1]); err != nil {
+		t.Fatal(err)
+	}
+	if e := sentinels.DefaultHistory.Entry(tokens[0]); e == nil || e.Vetoed {
+		t.Errorf("vetoing the second visitor's setup changed the first's: %+v", e)
+	}
+	if m := token.FindStringSubmatch(post(h, form).Body.String()); m == nil || m[1] == tokens[0] || m[1] == tokens[1] {
+		t.Error("a vetoed result was reused")
+	}
+}
+
+func TestQuizScoreIsKeptWithThePlayer(t *testing.T) {
+	st := &storage.MemoryStore{}
+	h := newTestHandler(t, &Config{Engine: &fakeEngine{}, Store: st})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/quiz?player=Ann", nil))
+	form := url.Values{"guess": {"50"}, "player": {"Ann"}}
+	r := httptest.NewRequest("POST", "/quiz", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.AddCookie(w.Result().Cookies()[0])
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	ps, err := st.Profiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pl := ps.Players["Ann"]; pl == nil || pl.Quiz == nil || pl.Quiz.Guesses != 1 {
+		t.Fatalf("stored player is %+v", pl)
+	}
+	// a new server, as after a restart, has the score too.
+	h = newTestHandler(t, &Config{Engine: &fakeEngine{}, Store: st})
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/quiz?player=ann", nil))
+	if !strings.Contains(w.Body.String(), "1 guess,") {
+		t.Errorf("quiz page doesn't show the stored score:\n%s", w.Body)
+	}
+}
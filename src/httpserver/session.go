@@ -0,0 +1,177 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// sessionTTL is how long an idle session is kept.
+const sessionTTL = 6 * time.Hour
+
+// maxDrafts is the most drafts a session keeps awaiting picks.
+const maxDrafts = 8
+
+// session is one visitor's sequence of setups, so rerolls can be undone and
+// redone.
+type session struct {
+	mu      sync.Mutex
+	used    time.Time
+	overlay string             // key of the session's stream overlay
+	undo    []*sentinels.Setup // earlier setups, oldest first
+	cur     *sentinels.Setup
+	redo    []*sentinels.Setup           // undone setups, most recently undone last
+	quiz    *sentinels.Setup             // the quiz setup awaiting a guess
+	drafts  map[string]*sentinels.Params // parameters of drafts awaiting picks, by token
+	score   sentinels.QuizScore          // of guesses made without a player name
+}
+
+var (
+	sessionMu sync.Mutex
+	sessions  = make(map[string]*session)
+)
+
+// getSession returns the visitor's session, starting a new one and setting
+// its cookie if there is none.
+func getSession(w http.ResponseWriter, r *http.Request) *session {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	now := time.Now()
+	for id, ss := range sessions {
+		if now.Sub(ss.used) > sessionTTL {
+			delete(sessions, id)
+		}
+	}
+	if c, err := r.Cookie("session"); err == nil {
+		if ss, ok := sessions[c.Value]; ok {
+			ss.used = now
+			return ss
+		}
+	}
+	id := fmt.Sprintf("%016x", sentinels.NewSeed())
+	ss := &session{used: now, overlay: fmt.Sprintf("%016x", sentinels.NewSeed())}
+	sessions[id] = ss
+	http.SetCookie(w, &http.Cookie{Name: "session", Value: id, Path: "/", HttpOnly: true})
+	return ss
+}
+
+// overlaySession returns the session with the given overlay key, or nil if
+// there is none.  Unlike getSession it doesn't keep the session alive, so an
+// overlay left open doesn't outlast the table.
+func overlaySession(key string) *session {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	for _, ss := range sessions {
+		if key != "" && ss.overlay == key {
+			return ss
+		}
+	}
+	return nil
+}
+
+// push makes s the current setup, forgetting anything undone.  Pushing the
+// current setup again changes nothing.
+func (ss *session) push(s *sentinels.Setup) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if s == ss.cur {
+		return
+	}
+	if ss.cur != nil {
+		ss.undo = append(ss.undo, ss.cur)
+	}
+	ss.cur, ss.redo = s, nil
+}
+
+// back returns to the previous setup, or returns nil if there is none.
+func (ss *session) back() *sentinels.Setup {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if len(ss.undo) == 0 {
+		return nil
+	}
+	s := ss.undo[len(ss.undo)-1]
+	ss.undo = ss.undo[:len(ss.undo)-1]
+	ss.redo = append(ss.redo, ss.cur)
+	sentinels.Reinstate(s, ss.cur)
+	ss.cur = s
+	return s
+}
+
+// forward redoes the last undone setup, or returns nil if there is none.
+func (ss *session) forward() *sentinels.Setup {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if len(ss.redo) == 0 {
+		return nil
+	}
+	s := ss.redo[len(ss.redo)-1]
+	ss.redo = ss.redo[:len(ss.redo)-1]
+	ss.undo = append(ss.undo, ss.cur)
+	sentinels.Reinstate(s, ss.cur)
+	ss.cur = s
+	return s
+}
+
+// addDraft keeps the parameters of a dealt draft until its picks come
+// back, returning the token the draft page posts them with.
+func (ss *session) addDraft(p *sentinels.Params) string {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.drafts == nil {
+		ss.drafts = make(map[string]*sentinels.Params)
+	}
+	for t := range ss.drafts {
+		if len(ss.drafts) < maxDrafts {
+			break
+		}
+		delete(ss.drafts, t)
+	}
+	t := fmt.Sprintf("%016x", sentinels.NewSeed())
+	ss.drafts[t] = p
+	return t
+}
+
+// takeDraft returns and forgets the parameters of the draft with the given
+// token, or returns nil if there is none.
+func (ss *session) takeDraft(token string) *sentinels.Params {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	p := ss.drafts[token]
+	delete(ss.drafts, token)
+	return p
+}
+
+// current returns the current setup.
+func (ss *session) current() *sentinels.Setup {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.cur
+}
+
+// previous returns the setup before the current one, or nil if there is
+// none.
+func (ss *session) previous() *sentinels.Setup {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if len(ss.undo) == 0 {
+		return nil
+	}
+	return ss.undo[len(ss.undo)-1]
+}
+
+// canUndo and canRedo report whether there is anything to undo or redo.
+func (ss *session) canUndo() bool {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return len(ss.undo) > 0
+}
+
+func (ss *session) canRedo() bool {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return len(ss.redo) > 0
+}
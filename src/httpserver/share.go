@@ -0,0 +1,39 @@
+package httpserver
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/uhhhclem/sentinels"
+	"github.com/uhhhclem/sentinels/qr"
+)
+
+// shared shows the setup described by a link from the result page.
+func (sv *server) shared(w http.ResponseWriter, r *http.Request) {
+	res := newResult(w, r)
+	s, err := sentinels.ParseShareQuery(r.URL.RawQuery)
+	if err != nil {
+		res.Msg = err.Error()
+		sv.render(w, "result.html", res)
+		return
+	}
+	sv.display(w, res, s, "")
+}
+
+// qrCode serves a PNG QR code of the link to the setup in the query, so it
+// can be scanned from the result page.
+func qrCode(w http.ResponseWriter, r *http.Request) {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	c, err := qr.Encode(scheme + "://" + r.Host + "/score?" + r.URL.RawQuery)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	if err := c.WritePNG(w, 4); err != nil {
+		log.Println(err)
+	}
+}
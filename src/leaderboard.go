@@ -0,0 +1,98 @@
+package sentinels
+
+import "sort"
+
+// LeaderboardMinGames is the number of games with a recorded result a
+// group must have played to be ranked by calibration.
+const LeaderboardMinGames = 5
+
+// Standing is one group's place on the leaderboards.
+type Standing struct {
+	Group   string // the group's alias, or its profile name if it has none
+	Games   int    // games with a recorded result
+	Wins    int
+	Hardest int // highest expected loss percentage among the games won
+	// Calibration is the mean squared difference between each game's
+	// expected loss and its result (the Brier score): 0 if the predictions
+	// were perfect, 0.25 if they were no better than a coin toss.
+	Calibration float64
+}
+
+// Leaderboard ranks the groups that have opted in by the hardest setup
+// they have beaten and by how well their games matched the predictions.
+type Leaderboard struct {
+	Hardest     []*Standing // hardest win first
+	Calibration []*Standing // best calibrated first; groups with few games are left out
+}
+
+// Leaderboard ranks the games in the history recorded for the groups in ps
+// that have opted in; see Profile.Leaderboard.  Other groups' games, and
+// games recorded for no group, aren't counted.
+func (h *History) Leaderboard(ps *Profiles) *Leaderboard {
+	ps.mu.Lock()
+	byGroup := make(map[string]*Standing)
+	for n, pr := range ps.ByName {
+		if pr.Leaderboard {
+			byGroup[n] = &Standing{Group: pr.publicName()}
+		}
+	}
+	ps.mu.Unlock()
+
+	h.mu.Lock()
+	sq := make(map[*Standing]float64)
+	for _, e := range h.Entries {
+		st := byGroup[e.Group]
+		if st == nil || e.Result == "" {
+			continue
+		}
+		st.Games++
+		lost := 1.0
+		if e.Result == Won {
+			st.Wins++
+			lost = 0
+			if e.LossPercent > st.Hardest {
+				st.Hardest = e.LossPercent
+			}
+		}
+		d := float64(e.LossPercent)/100 - lost
+		sq[st] += d * d
+	}
+	h.mu.Unlock()
+
+	lb := &Leaderboard{}
+	for _, st := range byGroup {
+		if st.Games == 0 {
+			continue
+		}
+		st.Calibration = sq[st] / float64(st.Games)
+		if st.Wins > 0 {
+			lb.Hardest = append(lb.Hardest, st)
+		}
+		if st.Games >= LeaderboardMinGames {
+			lb.Calibration = append(lb.Calibration, st)
+		}
+	}
+	sort.Slice(lb.Hardest, func(i, j int) bool {
+		a, b := lb.Hardest[i], lb.Hardest[j]
+		if a.Hardest != b.Hardest {
+			return a.Hardest > b.Hardest
+		}
+		return a.Group < b.Group
+	})
+	sort.Slice(lb.Calibration, func(i, j int) bool {
+		a, b := lb.Calibration[i], lb.Calibration[j]
+		if a.Calibration != b.Calibration {
+			return a.Calibration < b.Calibration
+		}
+		return a.Group < b.Group
+	})
+	return lb
+}
+
+// publicName is the name the profile's group is shown by to others.
+func (pr *Profile) publicName() string {
+	if pr.Alias != "" {
+		return pr.Alias
+	}
+	return pr.Name
+}
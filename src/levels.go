@@ -0,0 +1,41 @@
+package sentinels
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is a named difficulty, for players who would rather not think in
+// loss percentages.
+type Level struct {
+	Name        string
+	LossPercent int
+}
+
+// Levels are the named difficulties, easiest first.
+var Levels = []Level{
+	{"casual", 35},
+	{"standard", 50},
+	{"heroic", 70},
+	{"nightmare", 90},
+}
+
+// LevelNames returns the names of the levels, easiest first.
+func LevelNames() []string {
+	var names []string
+	for _, l := range Levels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+// ParseLevel returns the target loss percentage of the named level,
+// ignoring case.
+func ParseLevel(name string) (int, error) {
+	for _, l := range Levels {
+		if strings.EqualFold(l.Name, name) {
+			return l.LossPercent, nil
+		}
+	}
+	return 0, fmt.Errorf("Unknown difficulty %q; try %s.", name, strings.Join(LevelNames(), ", "))
+}
@@ -0,0 +1,151 @@
+package sentinels
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// LintData cross-checks the names in full card data, or the built-in data
+// if data is nil, against each other and against the tables keyed by name:
+// Base and Nemesis references, ExpansionCards, LocalizedNames and the
+// conflicts.  It also reports names so alike that one is likely a typo of
+// the other, such as two dates for the same place.  Where a name is
+// unknown, the message suggests the closest one.
+func LintData(data []byte) []Diagnostic {
+	if data == nil {
+		data = sdBytes
+	}
+	d := &SentinelsData{}
+	if err := json.Unmarshal(data, d); err != nil {
+		return []Diagnostic{{"error", "data", 0, "", err.Error()}}
+	}
+	var ds []Diagnostic
+	var all []string
+	byType := make(map[string][]string)
+	lists := []struct {
+		typ   string
+		cards []Difficulty
+	}{{"hero", d.Difficulty.Hero}, {"villain", d.Difficulty.Villain}, {"env", d.Difficulty.Env}}
+	for _, l := range lists {
+		for _, c := range l.cards {
+			byType[l.typ] = append(byType[l.typ], c.Name)
+			all = append(all, c.Name)
+		}
+	}
+	unknown := func(name string, names []string) string {
+		if s := closestName(name, names); s != "" {
+			return fmt.Sprintf("; did you mean %q?", s)
+		}
+		return ""
+	}
+	for _, l := range lists {
+		for i, c := range l.cards {
+			if c.Base != "" && !inList(c.Base, byType[l.typ]) {
+				ds = append(ds, Diagnostic{"error", l.typ, i, c.Name, fmt.Sprintf("base %q is not a %s%s", c.Base, l.typ, unknown(c.Base, byType[l.typ]))})
+			}
+			if c.Nemesis != "" && !inList(c.Nemesis, byType["villain"]) {
+				ds = append(ds, Diagnostic{"error", l.typ, i, c.Name, fmt.Sprintf("nemesis %q is not a villain%s", c.Nemesis, unknown(c.Nemesis, byType["villain"]))})
+			}
+		}
+	}
+	listed := make(map[string]ExpansionType)
+	for e := range ExpansionNames {
+		for i, n := range ExpansionCards[ExpansionType(e)] {
+			if !inList(n, all) {
+				ds = append(ds, Diagnostic{"error", "expansion", i, n, fmt.Sprintf("listed in %s but not in the data%s", ExpansionType(e), unknown(n, all))})
+			} else if prev, ok := listed[n]; ok {
+				ds = append(ds, Diagnostic{"warning", "expansion", i, n, fmt.Sprintf("listed in both %s and %s", prev, ExpansionType(e))})
+			} else {
+				listed[n] = ExpansionType(e)
+			}
+		}
+	}
+	var langs []string
+	for lang := range LocalizedNames {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		var names []string
+		for n := range LocalizedNames[lang] {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for i, n := range names {
+			if !inList(n, all) {
+				ds = append(ds, Diagnostic{"warning", "names", i, n, fmt.Sprintf("has a %s translation but is not in the data%s", lang, unknown(n, all))})
+			}
+		}
+	}
+	for i, c := range d.Conflicts {
+		if !inList(c.Villain, byType["villain"]) {
+			ds = append(ds, Diagnostic{"error", "conflict", i, c.Villain, "not a villain" + unknown(c.Villain, byType["villain"])})
+		}
+		if !inList(c.Environment, byType["env"]) {
+			ds = append(ds, Diagnostic{"error", "conflict", i, c.Environment, "not an environment" + unknown(c.Environment, byType["env"])})
+		}
+	}
+	// names differing only in case, punctuation or digits.
+	seen := make(map[string]string)
+	for i, n := range all {
+		k := strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, n)
+		if prev, ok := seen[k]; ok && prev != n {
+			ds = append(ds, Diagnostic{"warning", "name", i, n, fmt.Sprintf("is very like %q; check which is right", prev)})
+		} else {
+			seen[k] = n
+		}
+	}
+	return ds
+}
+
+// closestName returns the name in names nearest to name, ignoring case, or
+// "" if none is near enough to be a likely fix.
+func closestName(name string, names []string) string {
+	best, bestDist := "", len(name)/3+1
+	for _, n := range names {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(n)); d < bestDist {
+			best, bestDist = n, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,34 @@
+package sentinels
+
+// DifficultyModel scores setups.  The default model adds up the published
+// difficulty points; other models (simulations, fitted models) can be
+// installed by assigning Model.
+type DifficultyModel interface {
+	// Score returns the setup's difficulty total; higher is harder.
+	Score(s *Setup) int
+	// WinProbability returns the chance, between 0 and 1, that the heroes win.
+	WinProbability(s *Setup) float64
+}
+
+// Model is the difficulty model used to score generated setups.
+var Model DifficultyModel = PointsModel{}
+
+// PointsModel scores a setup as the sum of its cards' points (the villain's
+// advanced score in advanced mode, and the heroes' points adjusted for the
+// number of heroes) and the player count modifier, and converts scores to
+// probabilities using the scale.
+type PointsModel struct{}
+
+// Score implements DifficultyModel.
+func (PointsModel) Score(s *Setup) int {
+	t := s.PcPoints + s.VillainPoints() + s.Environment.Points
+	for _, h := range s.Heroes {
+		t += s.HeroPoints(h)
+	}
+	return t
+}
+
+// WinProbability implements DifficultyModel.
+func (m PointsModel) WinProbability(s *Setup) float64 {
+	return 1 - float64(LossPercentFor(m.Score(s), len(s.Heroes)))/100
+}
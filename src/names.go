@@ -0,0 +1,77 @@
+package sentinels
+
+import "strings"
+
+// DisplayName returns the card's name in the given language, falling back to
+// the canonical English name when no translation is known.  lang may be a
+// bare language code ("es") or a locale ("de-DE").
+func (c *Card) DisplayName(lang string) string {
+	if names, ok := LocalizedNames[baseLang(lang)]; ok {
+		if n, ok := names[c.Name]; ok {
+			return n
+		}
+	}
+	return c.Name
+}
+
+// baseLang reduces a locale such as "es-MX" or "de_DE" to its language code.
+func baseLang(lang string) string {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// LocalizedNames maps a language code to translations of canonical card
+// names.  Names that were kept in English by the published editions are
+// omitted.
+var LocalizedNames = map[string]map[string]string{
+	"es": {
+		"The Visionary":           "La Visionaria",
+		"The Argent Adept":        "El Adepto Argénteo",
+		"The Naturalist":          "El Naturalista",
+		"The Scholar":             "El Erudito",
+		"The Sentinels":           "Los Centinelas",
+		"Baron Blade":             "Barón Blade",
+		"The Chairman":            "El Presidente",
+		"The Matriarch":           "La Matriarca",
+		"The Dreamer":             "La Soñadora",
+		"Vengeful Five":           "Los Cinco Vengativos",
+		"Citizen Dawn":            "Ciudadana Dawn",
+		"Grand Warlord Voss":      "Gran Señor de la Guerra Voss",
+		"Plague Rat":              "Rata de la Plaga",
+		"Ruins of Atlantis":       "Ruinas de la Atlántida",
+		"Pike Industrial Complex": "Complejo Industrial Pike",
+		"Time Cataclysm":          "Cataclismo Temporal",
+		"Tomb of Anubis":          "Tumba de Anubis",
+		"Wagner Mars Base":        "Base Wagner en Marte",
+		"Mobile Defense Platform": "Plataforma de Defensa Móvil",
+		"Realm of Discord":        "Reino de la Discordia",
+		"Freedom Tower":           "Torre de la Libertad",
+		"The Final Wasteland":     "El Páramo Final",
+	},
+	"de": {
+		"The Visionary":           "Die Visionärin",
+		"The Argent Adept":        "Der Silberne Adept",
+		"The Naturalist":          "Der Naturalist",
+		"The Scholar":             "Der Gelehrte",
+		"The Sentinels":           "Die Sentinels",
+		"The Chairman":            "Der Vorsitzende",
+		"The Matriarch":           "Die Matriarchin",
+		"The Dreamer":             "Die Träumerin",
+		"Vengeful Five":           "Die Rachsüchtigen Fünf",
+		"Citizen Dawn":            "Bürgerin Dawn",
+		"Grand Warlord Voss":      "Großkriegsherr Voss",
+		"Plague Rat":              "Pestratte",
+		"Ruins of Atlantis":       "Ruinen von Atlantis",
+		"Pike Industrial Complex": "Pike-Industriekomplex",
+		"Time Cataclysm":          "Zeitkatastrophe",
+		"Tomb of Anubis":          "Grab des Anubis",
+		"Wagner Mars Base":        "Wagner-Marsbasis",
+		"Mobile Defense Platform": "Mobile Verteidigungsplattform",
+		"Realm of Discord":        "Reich der Zwietracht",
+		"Freedom Tower":           "Freiheitsturm",
+		"The Final Wasteland":     "Das Letzte Ödland",
+	},
+}
@@ -0,0 +1,87 @@
+package sentinels
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NemesisMode says whether setups pitting a hero against their nemesis are
+// wanted.
+type NemesisMode int
+
+const (
+	AnyNemesis NemesisMode = iota
+	// PreferNemesis looks for a setup in which a hero faces their nemesis,
+	// settling for one without if none turns up.
+	PreferNemesis
+	// AvoidNemesis never pits a hero against their nemesis.
+	AvoidNemesis
+)
+
+// NemesisModeNames are the names of the nemesis modes, indexed by
+// NemesisMode.
+var NemesisModeNames = []string{"any", "prefer", "avoid"}
+
+func (m NemesisMode) String() string {
+	if m < 0 || int(m) >= len(NemesisModeNames) {
+		return fmt.Sprintf("NemesisMode(%d)", int(m))
+	}
+	return NemesisModeNames[m]
+}
+
+// MarshalText encodes a nemesis mode by name, so presets stay readable.
+func (m NemesisMode) MarshalText() ([]byte, error) {
+	if m < 0 || int(m) >= len(NemesisModeNames) {
+		return nil, fmt.Errorf("Unknown nemesis mode %d.", int(m))
+	}
+	return []byte(NemesisModeNames[m]), nil
+}
+
+func (m *NemesisMode) UnmarshalText(b []byte) error {
+	v, err := ParseNemesisMode(string(b))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// ParseNemesisMode looks up a nemesis mode by name, ignoring case.  An empty
+// name is AnyNemesis.
+func ParseNemesisMode(name string) (NemesisMode, error) {
+	if name == "" {
+		return AnyNemesis, nil
+	}
+	for i, n := range NemesisModeNames {
+		if strings.EqualFold(n, name) {
+			return NemesisMode(i), nil
+		}
+	}
+	return AnyNemesis, fmt.Errorf("Unknown nemesis mode %q.", name)
+}
+
+// Nemeses returns the heroes in the setup who face their nemesis.
+func (s *Setup) Nemeses() []*Card {
+	var r []*Card
+	for _, h := range s.Heroes {
+		if h.Nemesis != "" && h.Nemesis == s.Villain.Base {
+			r = append(r, h)
+		}
+	}
+	return r
+}
+
+// nemesisPossible reports whether any hero in cs has their nemesis in it
+// too.
+func nemesisPossible(cs *CardSet) bool {
+	villains := make(map[string]bool)
+	for _, v := range cs.Villains {
+		villains[v.Base] = true
+	}
+	for _, h := range cs.Heroes {
+		if villains[h.Nemesis] {
+			return true
+		}
+	}
+	return false
+}
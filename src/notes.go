@@ -0,0 +1,53 @@
+package sentinels
+
+import (
+	"errors"
+	"strings"
+)
+
+// HouseRules are common house rules offered when taking notes on a setup;
+// others can be given as free text.
+var HouseRules = []string{
+	"Skip the villain's start-of-game ongoing",
+	"Heroes draw an extra card to start",
+	"No environment card on the first round",
+	"Incapacitated heroes keep their cards in play",
+	"The villain plays an extra card on its first turn",
+}
+
+// Annotate sets the notes and house rules of the entry with the given
+// token, replacing any it had.  Blank and repeated rules are dropped.
+func (h *History) Annotate(token, notes string, rules []string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range h.Entries {
+		if e.Token == token {
+			e.Notes = strings.TrimSpace(notes)
+			e.HouseRules = cleanRules(rules)
+			h.save()
+			return nil
+		}
+	}
+	return errors.New("No setup with that token in the history.")
+}
+
+// Annotate sets the setup's notes and house rules and those of its history
+// entry.
+func (s *Setup) Annotate(notes string, rules []string) error {
+	if err := DefaultHistory.Annotate(s.Token, notes, rules); err != nil {
+		return err
+	}
+	s.Notes, s.HouseRules = strings.TrimSpace(notes), cleanRules(rules)
+	return nil
+}
+
+// cleanRules returns rules without blank or repeated entries.
+func cleanRules(rules []string) []string {
+	var r []string
+	for _, v := range rules {
+		if v = strings.TrimSpace(v); v != "" && !inList(v, r) {
+			r = append(r, v)
+		}
+	}
+	return r
+}
@@ -0,0 +1,74 @@
+package sentinels
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// numberFormat is how a language writes numbers.
+type numberFormat struct {
+	decimal string // decimal separator
+	group   string // thousands separator
+	// minGroup is the fewest integer digits that are grouped; Spanish, for
+	// one, writes 1234 but 12.345.
+	minGroup int
+	percent  string // format of a percentage, given the formatted number
+}
+
+// numberFormats maps a language code to its number format.  Languages not
+// listed are written as English is.
+var numberFormats = map[string]numberFormat{
+	"en": {".", ",", 4, "%s%%"},
+	"es": {",", ".", 5, "%s\u00a0%%"},
+	"de": {",", ".", 4, "%s\u00a0%%"},
+	"fr": {",", "\u202f", 4, "%s\u00a0%%"},
+	"it": {",", ".", 4, "%s%%"},
+	"pt": {",", ".", 4, "%s%%"},
+	"nl": {",", ".", 4, "%s%%"},
+}
+
+// formatFor returns the number format for a language or locale.
+func formatFor(lang string) numberFormat {
+	if nf, ok := numberFormats[baseLang(lang)]; ok {
+		return nf
+	}
+	return numberFormats["en"]
+}
+
+// FormatNumber writes f with prec decimal places as it is written in the
+// given language; see DisplayName.
+func FormatNumber(lang string, f float64, prec int) string {
+	nf := formatFor(lang)
+	s := strconv.FormatFloat(math.Abs(f), 'f', prec, 64)
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], nf.decimal+s[i+1:]
+	}
+	if len(whole) >= nf.minGroup {
+		var b strings.Builder
+		for i, d := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteString(nf.group)
+			}
+			b.WriteRune(d)
+		}
+		whole = b.String()
+	}
+	if f < 0 && strings.Trim(s, "0.") != "" {
+		whole = "-" + whole
+	}
+	return whole + frac
+}
+
+// FormatInt writes n as it is written in the given language.
+func FormatInt(lang string, n int) string {
+	return FormatNumber(lang, float64(n), 0)
+}
+
+// FormatPercent writes a whole percentage, such as a LossPercent, as it is
+// written in the given language.
+func FormatPercent(lang string, pct int) string {
+	return fmt.Sprintf(formatFor(lang).percent, FormatInt(lang, pct))
+}
@@ -0,0 +1,55 @@
+package sentinels
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Phase is a stage of a game against a villain, such as after its card
+// flips, that is much harder or easier than the villain is overall.
+type Phase struct {
+	When   string // when the phase begins, e.g. "flips"
+	Points int    // difficulty relative to the villain's points
+}
+
+// SpikePoints is how far a phase's points must be from zero for Describe
+// to warn that the game will change when it begins.
+const SpikePoints = 20
+
+// Spikes returns the villain's phases that are at least SpikePoints harder
+// or easier than the villain is overall.
+func (c *Card) Spikes() []Phase {
+	var ps []Phase
+	for _, p := range c.Phases {
+		if abs(p.Points) >= SpikePoints {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// phaseAdvice warns of the villain's phases in a sentence, e.g. "Expect the
+// game to spike when Gloomweaver flips (+45).", or returns "" if the
+// villain has none worth mentioning.
+func (s *Setup) phaseAdvice() string {
+	var harder, easier []string
+	for _, p := range s.Villain.Spikes() {
+		w := fmt.Sprintf("when %s %s (%s)", s.Villain.DisplayName(s.Lang), p.When, signed(p.Points))
+		if p.Points > 0 {
+			harder = append(harder, w)
+		} else {
+			easier = append(easier, w)
+		}
+	}
+	var parts []string
+	if len(harder) > 0 {
+		parts = append(parts, "to spike "+strings.Join(harder, " and "))
+	}
+	if len(easier) > 0 {
+		parts = append(parts, "to ease off "+strings.Join(easier, " and "))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Expect the game " + strings.Join(parts, " and ") + "."
+}
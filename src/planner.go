@@ -0,0 +1,66 @@
+package sentinels
+
+import (
+	"errors"
+	"time"
+)
+
+// BaseMinutes is the time a game takes apart from its heroes, villain and
+// environment: setup, teardown and the villain's turns.
+const BaseMinutes = 20
+
+// HeroMinutes is the time each hero adds to a game.
+const HeroMinutes = 12
+
+// planAttempts is the number of setups Plan tries for each game before
+// deciding none will fit in the time left.
+const planAttempts = 20
+
+// Duration estimates how long the setup takes to play.
+func (s *Setup) Duration() time.Duration {
+	m := BaseMinutes + HeroMinutes*len(s.Heroes) + s.Villain.Minutes + s.Environment.Minutes
+	return time.Duration(m) * time.Minute
+}
+
+// Plan fills an evening of length window with as many games as fit, taking
+// their targets in turn from lps and starting over when they run out.  Each
+// game uses a villain not yet played that evening.  The other parameters
+// come from p.  Only the planned games are recorded in the history.
+func Plan(p *Params, window time.Duration, lps []int) ([]*Setup, error) {
+	if len(lps) == 0 {
+		lps = []int{p.LossPercent}
+	}
+	var r []*Setup
+	left := window
+	q := *p
+	q.ByTotal = false
+	q.Exclude = append([]string(nil), p.Exclude...)
+	for {
+		q.LossPercent = lps[len(r)%len(lps)]
+		qs, err := newSearchFor(&q)
+		if err != nil {
+			return nil, err
+		}
+		var s *Setup
+		for i := 0; i < planAttempts && s == nil; i++ {
+			c, _, err := qs.run(NewSeed())
+			if err != nil {
+				break
+			}
+			if c.Duration() <= left {
+				s = c
+			}
+		}
+		if s == nil {
+			break
+		}
+		qs.register(s, DefaultVetoes, "")
+		r = append(r, s)
+		left -= s.Duration()
+		q.Exclude = append(q.Exclude, s.Villain.Name)
+	}
+	if len(r) == 0 {
+		return nil, errors.New("No setup fits in the time available.")
+	}
+	return r, nil
+}
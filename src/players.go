@@ -0,0 +1,246 @@
+package sentinels
+
+import (
+	"sort"
+	"strings"
+)
+
+// Player holds a registered player's hero preferences.
+type Player struct {
+	Name      string
+	Favorites []string `json:",omitempty"` // heroes the player would rather play
+	Banned    []string `json:",omitempty"` // heroes the player won't play
+	// Comfort is the most complex hero the player is happy with, from 1
+	// to 3; 0 means any.
+	Comfort int `json:",omitempty"`
+	// Recent lists the archetypes of the heroes the player had in their
+	// latest games, most recent first, for fair seating.
+	Recent []string `json:",omitempty"`
+	// Quiz is the player's score in the web app's guessing game, if they
+	// have played it.
+	Quiz *QuizScore `json:",omitempty"`
+}
+
+// QuizScore is how well a player has guessed setups' loss percentages.
+type QuizScore struct {
+	Guesses    int
+	TotalError int // sum of the guesses' distances from the predictions
+	Close      int // guesses near enough to the prediction to count as close
+}
+
+// Add counts a guess off points from the prediction.
+func (qs *QuizScore) Add(off int, close bool) {
+	qs.Guesses++
+	qs.TotalError += off
+	if close {
+		qs.Close++
+	}
+}
+
+// MeanError returns the average distance of the guesses from the
+// predictions, in percentage points.
+func (qs QuizScore) MeanError() float64 {
+	if qs.Guesses == 0 {
+		return 0
+	}
+	return float64(qs.TotalError) / float64(qs.Guesses)
+}
+
+// fairnessWindow is the number of a player's recent games fair seating
+// considers.
+const fairnessWindow = 3
+
+// seatScore rates giving the hero to the player: favorites count for, and
+// archetypes the player had recently count against, more so the more
+// recently.
+func (pl *Player) seatScore(c *Card) int {
+	score := 0
+	if pl.Likes(c) {
+		score += fairnessWindow
+	}
+	for i, a := range pl.Recent {
+		if i < fairnessWindow && a == c.Archetype {
+			score -= fairnessWindow - i
+		}
+	}
+	return score
+}
+
+// CanPlay reports whether the player will play the hero.
+func (pl *Player) CanPlay(c *Card) bool {
+	if pl.Comfort > 0 && c.Complexity > pl.Comfort {
+		return false
+	}
+	return !namesCard(pl.Banned, c.Name)
+}
+
+// Likes reports whether the hero is one of the player's favorites.
+func (pl *Player) Likes(c *Card) bool {
+	return namesCard(pl.Favorites, c.Name)
+}
+
+// Player returns the named player, ignoring case, or nil if there is none.
+func (ps *Profiles) Player(name string) *Player {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for n, pl := range ps.Players {
+		if strings.EqualFold(n, strings.TrimSpace(name)) {
+			return pl
+		}
+	}
+	return nil
+}
+
+// PutPlayer adds or replaces a player.
+func (ps *Profiles) PutPlayer(pl *Player) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.Players == nil {
+		ps.Players = make(map[string]*Player)
+	}
+	ps.Players[pl.Name] = pl
+	return ps.save()
+}
+
+// AddQuizGuess adds a guess off points from the prediction to the named
+// player's quiz score, registering the player if there is none by that
+// name, saves it and returns the new score.
+func (ps *Profiles) AddQuizGuess(name string, off int, close bool) (QuizScore, error) {
+	name = strings.TrimSpace(name)
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	var pl *Player
+	for n, p := range ps.Players {
+		if strings.EqualFold(n, name) {
+			pl = p
+		}
+	}
+	if pl == nil {
+		if ps.Players == nil {
+			ps.Players = make(map[string]*Player)
+		}
+		pl = &Player{Name: name}
+		ps.Players[name] = pl
+	}
+	if pl.Quiz == nil {
+		pl.Quiz = &QuizScore{}
+	}
+	pl.Quiz.Add(off, close)
+	return *pl.Quiz, ps.save()
+}
+
+// QuizScore returns the named player's quiz score, which is zero if they
+// haven't played or aren't registered.
+func (ps *Profiles) QuizScore(name string) QuizScore {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for n, pl := range ps.Players {
+		if strings.EqualFold(n, strings.TrimSpace(name)) && pl.Quiz != nil {
+			return *pl.Quiz
+		}
+	}
+	return QuizScore{}
+}
+
+// RemovePlayer removes the named player.
+func (ps *Profiles) RemovePlayer(name string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	delete(ps.Players, name)
+	return ps.save()
+}
+
+// PlayerNames returns the names of the players in sorted order.
+func (ps *Profiles) PlayerNames() []string {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	var names []string
+	for n := range ps.Players {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Team looks up the named players.  Names not in the registry get a player
+// with no preferences, so every name has a seat.
+func (ps *Profiles) Team(names []string) []*Player {
+	var team []*Player
+	for _, n := range names {
+		if n = strings.TrimSpace(n); n == "" {
+			continue
+		}
+		pl := ps.Player(n)
+		if pl == nil {
+			pl = &Player{Name: n}
+		}
+		team = append(team, pl)
+	}
+	return team
+}
+
+// recentTeam returns copies of the players with the archetypes of the
+// heroes they had in their latest games, according to the history.
+func (h *History) recentTeam(team []*Player) []*Player {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := make([]*Player, len(team))
+	for i, pl := range team {
+		c := *pl
+		c.Recent = nil
+		for j := len(h.Entries) - 1; j >= 0 && len(c.Recent) < fairnessWindow; j-- {
+			e := h.Entries[j]
+			if e.Vetoed {
+				continue
+			}
+			for hero, name := range e.Seats {
+				if name == pl.Name {
+					if card, ok := Lookup(hero); ok {
+						c.Recent = append(c.Recent, card.Archetype)
+					}
+				}
+			}
+		}
+		r[i] = &c
+	}
+	return r
+}
+
+// seatTeam assigns the team to the heroes so that nobody gets a hero they
+// won't play, with the best total seatScore, and sets a random turn order.
+// It returns nil if there is no acceptable assignment.
+func seatTeam(heroes []*Card, team []*Player, rng searchRand) []Seat {
+	var best []int
+	bestScore := 0
+	perm := make([]int, len(heroes))
+	used := make([]bool, len(heroes))
+	// try the heroes in a random order, so ties are broken at random.
+	try := rng.Perm(len(heroes))
+	var assign func(i, score int)
+	assign = func(i, score int) {
+		if i == len(heroes) || i == len(team) {
+			if best == nil || score > bestScore {
+				best, bestScore = append([]int(nil), perm[:i]...), score
+			}
+			return
+		}
+		for _, h := range try {
+			if used[h] || !team[i].CanPlay(heroes[h]) {
+				continue
+			}
+			used[h], perm[i] = true, h
+			assign(i+1, score+team[i].seatScore(heroes[h]))
+			used[h] = false
+		}
+	}
+	assign(0, 0)
+	if best == nil {
+		return nil
+	}
+	seats := make([]Seat, len(best))
+	order := rng.Perm(len(best))
+	for i, h := range best {
+		seats[order[i]] = Seat{Player: team[i].Name, Hero: heroes[h]}
+	}
+	return seats
+}
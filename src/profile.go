@@ -0,0 +1,152 @@
+package sentinels
+
+import (
+	"sort"
+	"sync"
+)
+
+// FamilyTags are the content tags excluded in family mode.
+var FamilyTags = []string{"dark"}
+
+// Profile holds a player's or group's saved preferences.
+type Profile struct {
+	Name        string
+	Expansions  []ExpansionType `json:",omitempty"`
+	Family      bool            // exclude cards tagged with FamilyTags
+	ExcludeTags []string        `json:",omitempty"`
+	// Copies counts the expansions owned more than once; see Params.Copies.
+	Copies map[ExpansionType]int `json:",omitempty"`
+	// Promos are the promo cards owned, if not all of them; see
+	// Params.Promos.
+	Promos []string `json:",omitempty"`
+	// Leaderboard opts the group into the instance's leaderboards, where
+	// it is shown by Alias, if that is set, rather than by Name.
+	Leaderboard bool   `json:",omitempty"`
+	Alias       string `json:",omitempty"`
+	// Rotation deals the group's villains from a shuffled bag, so that
+	// each in the collection comes up once before any comes up again, and
+	// HeroRotation and EnvRotation do the same for its heroes and
+	// environments.  The bags are independent; the Drawn lists hold the
+	// cards dealt from each since it was last refilled.  See Rotate.
+	Rotation          bool     `json:",omitempty"`
+	HeroRotation      bool     `json:",omitempty"`
+	EnvRotation       bool     `json:",omitempty"`
+	VillainsDrawn     []string `json:",omitempty"`
+	HeroesDrawn       []string `json:",omitempty"`
+	EnvironmentsDrawn []string `json:",omitempty"`
+}
+
+// Apply sets search parameters from the profile, recording the setups
+// found for its group.  Expansions, Copies and Promos are only replaced if
+// the profile lists some.
+func (pr *Profile) Apply(p *Params) {
+	p.Group = pr.Name
+	if len(pr.Expansions) > 0 {
+		p.Expansions = pr.Expansions
+	}
+	if pr.Family {
+		p.ExcludeTags = append(p.ExcludeTags, FamilyTags...)
+	}
+	p.ExcludeTags = append(p.ExcludeTags, pr.ExcludeTags...)
+	if len(pr.Copies) > 0 {
+		p.Copies = pr.Copies
+	}
+	if len(pr.Promos) > 0 {
+		p.Promos = pr.Promos
+	}
+	pr.Rotate(p)
+}
+
+// Profiles is a set of profiles, presets and players keyed by name.  If
+// Store is set, the set is saved there after every change.
+type Profiles struct {
+	mu      sync.Mutex
+	Store   Store `json:"-"`
+	ByName  map[string]*Profile
+	Presets map[string]*Params // named bundles of search parameters
+	Players map[string]*Player `json:",omitempty"`
+}
+
+// LoadProfiles reads profiles from a JSON file and saves later changes to
+// the same file.  A missing file yields an empty set.
+func LoadProfiles(path string) (*Profiles, error) {
+	return OpenProfiles(&FileStore{ProfilesPath: path})
+}
+
+// Get returns the named profile, or nil if there is none.
+func (ps *Profiles) Get(name string) *Profile {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return ps.ByName[name]
+}
+
+// Put adds or replaces a profile.
+func (ps *Profiles) Put(p *Profile) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.ByName == nil {
+		ps.ByName = make(map[string]*Profile)
+	}
+	ps.ByName[p.Name] = p
+	return ps.save()
+}
+
+// Preset returns a copy of the named preset, or nil if there is none.
+func (ps *Profiles) Preset(name string) *Params {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	p, ok := ps.Presets[name]
+	if !ok {
+		return nil
+	}
+	c := *p
+	return &c
+}
+
+// SavePreset adds or replaces a preset.  What is left of a rotation isn't
+// saved with it.
+func (ps *Profiles) SavePreset(name string, p *Params) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.Presets == nil {
+		ps.Presets = make(map[string]*Params)
+	}
+	c := *p
+	if c.Rotation {
+		c.Bag, c.Rotation = nil, false
+	}
+	ps.Presets[name] = &c
+	return ps.save()
+}
+
+// Names returns the names of the profiles in sorted order.
+func (ps *Profiles) Names() []string {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	var names []string
+	for n := range ps.ByName {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PresetNames returns the names of the presets in sorted order.
+func (ps *Profiles) PresetNames() []string {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	var names []string
+	for n := range ps.Presets {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// save writes the profiles to their store; the caller must hold ps.mu.
+func (ps *Profiles) save() error {
+	if ps.Store == nil {
+		return nil
+	}
+	return ps.Store.PutProfiles(ps)
+}
@@ -0,0 +1,504 @@
+// Package qr encodes text as QR codes, in byte mode at error correction
+// level L, for versions 1 to 20 (up to 858 bytes).
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// Code is an encoded QR code.
+type Code struct {
+	Size    int      // modules on a side
+	modules [][]bool // dark modules, by row then column
+}
+
+// blocks describes the error correction blocks of a version at level L:
+// the error correction codewords per block, then the number of blocks and
+// their data codewords in the first group, then the same for the second.
+type blocks struct {
+	ec, n1, d1, n2, d2 int
+}
+
+var versions = []blocks{
+	{},
+	{7, 1, 19, 0, 0},
+	{10, 1, 34, 0, 0},
+	{15, 1, 55, 0, 0},
+	{20, 1, 80, 0, 0},
+	{26, 1, 108, 0, 0},
+	{18, 2, 68, 0, 0},
+	{20, 2, 78, 0, 0},
+	{24, 2, 97, 0, 0},
+	{30, 2, 116, 0, 0},
+	{18, 2, 68, 2, 69},
+	{20, 4, 81, 0, 0},
+	{24, 2, 92, 2, 93},
+	{26, 4, 107, 0, 0},
+	{30, 3, 115, 1, 116},
+	{22, 5, 87, 1, 88},
+	{24, 5, 98, 1, 99},
+	{28, 1, 107, 5, 108},
+	{30, 5, 120, 1, 121},
+	{28, 3, 113, 4, 114},
+	{28, 3, 107, 5, 108},
+}
+
+func (b blocks) data() int { return b.n1*b.d1 + b.n2*b.d2 }
+
+// Encode encodes text in the smallest version that holds it.
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+	ver := 0
+	for v := 1; v < len(versions); v++ {
+		if 4+countBits(v)+8*len(data) <= 8*versions[v].data() {
+			ver = v
+			break
+		}
+	}
+	if ver == 0 {
+		return nil, errors.New("Too much text for a QR code.")
+	}
+	q := newCode(ver)
+	q.place(codewords(ver, data))
+	best, bestPenalty := -1, 0
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormat(mask)
+		if p := q.penalty(); best < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(best)
+	q.drawFormat(best)
+	q.function = nil
+	return q.Code, nil
+}
+
+// countBits is the length of the character count in byte mode.
+func countBits(ver int) int {
+	if ver < 10 {
+		return 8
+	}
+	return 16
+}
+
+// codewords encodes data in byte mode, pads it to the version's capacity,
+// and interleaves it with its error correction.
+func codewords(ver int, data []byte) []byte {
+	b := versions[ver]
+	var bits []bool
+	put := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, v>>uint(i)&1 == 1)
+		}
+	}
+	put(4, 4)
+	put(len(data), countBits(ver))
+	for _, c := range data {
+		put(int(c), 8)
+	}
+	capacity := 8 * b.data()
+	for i := 0; i < 4 && len(bits) < capacity; i++ {
+		bits = append(bits, false)
+	}
+	for len(bits)%8 != 0 {
+		bits = append(bits, false)
+	}
+	var all []byte
+	for i := 0; i < len(bits); i += 8 {
+		var c byte
+		for _, bit := range bits[i : i+8] {
+			c <<= 1
+			if bit {
+				c |= 1
+			}
+		}
+		all = append(all, c)
+	}
+	for pad := byte(0xEC); len(all) < b.data(); pad ^= 0xEC ^ 0x11 {
+		all = append(all, pad)
+	}
+
+	var dblocks, eblocks [][]byte
+	gen := generator(b.ec)
+	for i := 0; i < b.n1+b.n2; i++ {
+		n := b.d1
+		if i >= b.n1 {
+			n = b.d2
+		}
+		dblocks = append(dblocks, all[:n])
+		eblocks = append(eblocks, remainder(all[:n], gen))
+		all = all[n:]
+	}
+	var out []byte
+	for i := 0; i < b.d1 || i < b.d2; i++ {
+		for _, d := range dblocks {
+			if i < len(d) {
+				out = append(out, d[i])
+			}
+		}
+	}
+	for i := 0; i < b.ec; i++ {
+		for _, e := range eblocks {
+			out = append(out, e[i])
+		}
+	}
+	return out
+}
+
+// gfMul multiplies in GF(256) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMul(x, y byte) byte {
+	var z byte
+	for i := 7; i >= 0; i-- {
+		hi := z & 0x80
+		z <<= 1
+		if hi != 0 {
+			z ^= 0x1D
+		}
+		if y>>uint(i)&1 == 1 {
+			z ^= x
+		}
+	}
+	return z
+}
+
+// generator returns the Reed-Solomon generator polynomial of the given
+// degree, highest coefficient first, leaving out the leading 1.
+func generator(degree int) []byte {
+	g := make([]byte, degree)
+	g[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range g {
+			g[j] = gfMul(g[j], root)
+			if j+1 < len(g) {
+				g[j] ^= g[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+	return g
+}
+
+// remainder returns the error correction codewords for data.
+func remainder(data, gen []byte) []byte {
+	r := make([]byte, len(gen))
+	for _, b := range data {
+		f := b ^ r[0]
+		copy(r, r[1:])
+		r[len(r)-1] = 0
+		for i := range r {
+			r[i] ^= gfMul(gen[i], f)
+		}
+	}
+	return r
+}
+
+// builder is a code under construction.
+type builder struct {
+	*Code
+	function [][]bool // modules that aren't data
+}
+
+func newCode(ver int) *builder {
+	size := 4*ver + 17
+	q := &builder{Code: &Code{Size: size}}
+	for i := 0; i < size; i++ {
+		q.modules = append(q.modules, make([]bool, size))
+		q.function = append(q.function, make([]bool, size))
+	}
+	for i := 0; i < size; i++ {
+		q.set(6, i, i%2 == 0)
+		q.set(i, 6, i%2 == 0)
+	}
+	for _, p := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := p[0]+dx, p[1]+dy
+				if x >= 0 && x < size && y >= 0 && y < size {
+					d := max(abs(dx), abs(dy))
+					q.set(x, y, d != 2 && d != 4)
+				}
+			}
+		}
+	}
+	pos := alignment(ver)
+	last := len(pos) - 1
+	for i, y := range pos {
+		for j, x := range pos {
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+	q.drawFormat(0)
+	if ver >= 7 {
+		rem := ver
+		for i := 0; i < 12; i++ {
+			rem = rem<<1 ^ (rem>>11)*0x1F25
+		}
+		bits := ver<<12 | rem
+		for i := 0; i < 18; i++ {
+			a, b := size-11+i%3, i/3
+			q.set(a, b, bits>>uint(i)&1 == 1)
+			q.set(b, a, bits>>uint(i)&1 == 1)
+		}
+	}
+	return q
+}
+
+// alignment returns the centre coordinates of a version's alignment
+// patterns.
+func alignment(ver int) []int {
+	if ver == 1 {
+		return nil
+	}
+	n := ver/7 + 2
+	step := (ver*8 + n*3 + 5) / (n*4 - 4) * 2
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, 4*ver+10; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+// set sets a function module.
+func (q *builder) set(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.function[y][x] = true
+}
+
+// drawFormat draws both copies of the format information for level L and
+// the given mask.
+func (q *builder) drawFormat(mask int) {
+	data := 1<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>uint(i)&1 == 1 }
+	size := q.Size
+	for i := 0; i <= 5; i++ {
+		q.set(8, i, bit(i))
+	}
+	q.set(8, 7, bit(6))
+	q.set(8, 8, bit(7))
+	q.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		q.set(size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.set(8, size-15+i, bit(i))
+	}
+	q.set(8, size-8, true)
+}
+
+// place lays the codewords out in the zigzag order.
+func (q *builder) place(data []byte) {
+	i := 0
+	for right := q.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.Size - 1 - vert
+				}
+				if !q.function[y][x] && i < len(data)*8 {
+					q.modules[y][x] = data[i>>3]>>uint(7-i&7)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by a mask pattern; applying it
+// twice undoes it.
+func (q *builder) applyMask(mask int) {
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if flip && !q.function[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the code would be to scan: long runs, 2x2
+// blocks, finder-like patterns and an imbalance of dark and light.
+func (q *Code) penalty() int {
+	p, dark := 0, 0
+	at := func(x, y int, rows bool) bool {
+		if rows {
+			return q.modules[y][x]
+		}
+		return q.modules[x][y]
+	}
+	for _, rows := range []bool{true, false} {
+		for y := 0; y < q.Size; y++ {
+			run := 0
+			var line []bool
+			for x := 0; x < q.Size; x++ {
+				m := at(x, y, rows)
+				line = append(line, m)
+				if x > 0 && m == at(x-1, y, rows) {
+					run++
+					if run == 5 {
+						p += 3
+					} else if run > 5 {
+						p++
+					}
+				} else {
+					run = 1
+				}
+			}
+			for x := 0; x+11 <= q.Size; x++ {
+				if matches(line[x:x+11], "10111010000") || matches(line[x:x+11], "00001011101") {
+					p += 40
+				}
+			}
+		}
+	}
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x > 0 && y > 0 {
+				m := q.modules[y][x]
+				if m == q.modules[y-1][x] && m == q.modules[y][x-1] && m == q.modules[y-1][x-1] {
+					p += 3
+				}
+			}
+		}
+	}
+	total := q.Size * q.Size
+	k := (abs(dark*20-total*10) + total - 1) / total
+	return p + (k-1)*10*boolInt(k > 0)
+}
+
+func matches(line []bool, pattern string) bool {
+	for i, c := range pattern {
+		if line[i] != (c == '1') {
+			return false
+		}
+	}
+	return true
+}
+
+// Dark reports whether the module at column x, row y is dark.
+func (q *Code) Dark(x, y int) bool {
+	return q.modules[y][x]
+}
+
+// quiet is the width of the light border around a code.
+const quiet = 4
+
+// Text renders the code with Unicode half blocks, two rows of modules to
+// a line.  Dark modules are drawn as spaces and light ones as blocks, so
+// that the code scans on a terminal with light text on a dark background.
+func (q *Code) Text() string {
+	var b bytes.Buffer
+	light := func(x, y int) bool {
+		return x < 0 || y < 0 || x >= q.Size || y >= q.Size || !q.modules[y][x]
+	}
+	for y := -quiet; y < q.Size+quiet; y += 2 {
+		for x := -quiet; x < q.Size+quiet; x++ {
+			top, bottom := light(x, y), light(x, y+1) && y+1 < q.Size+quiet
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Image returns the code as an image with scale pixels per module.
+func (q *Code) Image(scale int) image.Image {
+	n := (q.Size + 2*quiet) * scale
+	img := image.NewGray(image.Rect(0, 0, n, n))
+	for py := 0; py < n; py++ {
+		for px := 0; px < n; px++ {
+			x, y := px/scale-quiet, py/scale-quiet
+			c := color.Gray{255}
+			if x >= 0 && y >= 0 && x < q.Size && y < q.Size && q.modules[y][x] {
+				c = color.Gray{0}
+			}
+			img.SetGray(px, py, c)
+		}
+	}
+	return img
+}
+
+// WritePNG writes the code as a PNG with scale pixels per module.
+func (q *Code) WritePNG(w io.Writer, scale int) error {
+	return png.Encode(w, q.Image(scale))
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package sentinels
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+)
+
+// SeedRecord holds what is needed to reproduce a generated setup.
+type SeedRecord struct {
+	Seed    int64
+	Params  Params
+	Exclude []string `json:",omitempty"` // keys of vetoed setups
+	Draws   int      // number of random numbers drawn
+	Key     string   // key of the setup produced
+	// RNG is the random number generator the search used, such as
+	// PCGRNG; records made before there was a choice have LegacyRNG.
+	RNG string `json:",omitempty"`
+}
+
+// record makes a seed record for a setup the search produced.
+func (q *search) record(seed int64, draws int, s *Setup) *SeedRecord {
+	r := &SeedRecord{Seed: seed, Params: q.params, Draws: draws, Key: s.Key(), RNG: q.rngKind}
+	for k := range q.exclude {
+		r.Exclude = append(r.Exclude, k)
+	}
+	sort.Strings(r.Exclude)
+	return r
+}
+
+// Replay regenerates the setup described by a seed record.  It returns an
+// error if the result differs, which means the card data or difficulty model
+// changed since the record was made.  Replayed setups are not added to the
+// history and can't be vetoed.
+func Replay(r *SeedRecord) (*Setup, error) {
+	// the recorded team already holds the recent games fair seating used.
+	p := r.Params
+	p.Fair = false
+	q, err := newSearchFor(&p)
+	if err != nil {
+		return nil, err
+	}
+	q.rngKind = r.RNG
+	for _, k := range r.Exclude {
+		q.exclude[k] = true
+	}
+	s, _, err := q.run(r.Seed)
+	if err != nil {
+		return nil, err
+	}
+	if s.Seed.Key != r.Key || s.Seed.Draws != r.Draws {
+		return s, errors.New("Replay produced a different setup; the card data or model has changed.")
+	}
+	return s, nil
+}
+
+// countingSource is a rand.Source that counts the numbers drawn from it.
+type countingSource struct {
+	src rand.Source
+	n   int
+}
+
+func (c *countingSource) Int63() int64 {
+	c.n++
+	return c.src.Int63()
+}
+
+func (c *countingSource) Seed(seed int64) {
+	c.src.Seed(seed)
+}
+
+// Sample finds a setup for the parameters using the given seed, e.g. to
+// draw many setups for analysis.  The same parameters and seed always give
+// the same setup.  Samples are not added to the history and can't be
+// vetoed.
+func Sample(p *Params, seed int64) (*Setup, error) {
+	q, err := newSearchFor(p)
+	if err != nil {
+		return nil, err
+	}
+	s, _, err := q.run(seed)
+	return s, err
+}
@@ -0,0 +1,62 @@
+package sentinels
+
+import (
+	"fmt"
+	"math/rand"
+	randv2 "math/rand/v2"
+)
+
+// Random number generators a SeedRecord can name.  Each search run has a
+// stream of its own, seeded from its seed, so concurrent searches don't
+// contend for a shared source.
+const (
+	// LegacyRNG is math/rand's original source, which made the seed
+	// records written before PCGRNG; they still replay with it.
+	LegacyRNG = ""
+	// PCGRNG is math/rand/v2's PCG, used by new searches.
+	PCGRNG = "pcg"
+)
+
+// pcgStream is the second word of PCG state, fixed so that a search's
+// numbers follow from its seed alone.
+const pcgStream = 0x5e471ee15
+
+// searchRand is the source of a search run's random numbers.
+type searchRand interface {
+	Intn(n int) int
+	Perm(n int) []int
+}
+
+// newSearchRand returns a generator of the given kind seeded with seed, and
+// a function reporting how many numbers have been drawn from it.
+func newSearchRand(kind string, seed int64) (searchRand, func() int, error) {
+	switch kind {
+	case LegacyRNG:
+		src := &countingSource{src: rand.NewSource(seed)}
+		return rand.New(src), func() int { return src.n }, nil
+	case PCGRNG:
+		src := &countingPCG{src: randv2.NewPCG(uint64(seed), pcgStream)}
+		return pcgRand{randv2.New(src)}, func() int { return src.n }, nil
+	}
+	return nil, nil, fmt.Errorf("Unknown random number generator %q.", kind)
+}
+
+// pcgRand adapts a math/rand/v2 generator to searchRand.
+type pcgRand struct {
+	*randv2.Rand
+}
+
+func (r pcgRand) Intn(n int) int {
+	return r.IntN(n)
+}
+
+// countingPCG is a PCG source that counts the numbers drawn from it.
+type countingPCG struct {
+	src *randv2.PCG
+	n   int
+}
+
+func (c *countingPCG) Uint64() uint64 {
+	c.n++
+	return c.src.Uint64()
+}
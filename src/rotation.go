@@ -0,0 +1,199 @@
+package sentinels
+
+import (
+	"fmt"
+	"log"
+)
+
+// Bag is one of a profile's rotations, for a collection.
+type Bag struct {
+	Type  CardType
+	On    bool     // whether setups for the group are dealt from it
+	Left  []string // cards in the collection not drawn this round
+	Drawn []string // cards drawn this round, in order
+}
+
+// Bag returns the profile's rotation of cards of type t in cs.
+func (pr *Profile) Bag(t CardType, cs *CardSet) *Bag {
+	return &Bag{Type: t, On: pr.rotates(t), Left: pr.undrawn(t, cs), Drawn: append([]string(nil), *pr.drawn(t)...)}
+}
+
+// rotates reports whether the profile deals cards of type t from a bag.
+func (pr *Profile) rotates(t CardType) bool {
+	switch t {
+	case Hero:
+		return pr.HeroRotation
+	case Villain:
+		return pr.Rotation
+	}
+	return pr.EnvRotation
+}
+
+// drawn returns the list of cards of type t drawn this round.
+func (pr *Profile) drawn(t CardType) *[]string {
+	switch t {
+	case Hero:
+		return &pr.HeroesDrawn
+	case Villain:
+		return &pr.VillainsDrawn
+	}
+	return &pr.EnvironmentsDrawn
+}
+
+// undrawn returns the names of the cards of type t in cs not drawn this
+// round, which is none once the round is over.
+func (pr *Profile) undrawn(t CardType, cs *CardSet) []string {
+	var left []string
+	for _, c := range cs.all() {
+		if c.Type == t && !inList(c.Name, *pr.drawn(t)) {
+			left = append(left, c.Name)
+		}
+	}
+	return left
+}
+
+// bag returns the names of the cards of type t in cs left in the profile's
+// rotation: those not drawn since the bag was last refilled or, once every
+// one has been drawn, all of them again.
+func (pr *Profile) bag(t CardType, cs *CardSet) []string {
+	if left := pr.undrawn(t, cs); len(left) > 0 {
+		return left
+	}
+	var all []string
+	for _, c := range cs.all() {
+		if c.Type == t {
+			all = append(all, c.Name)
+		}
+	}
+	return all
+}
+
+// Rotate limits a search to the cards left in the profile's rotations:
+// villains if it has Rotation set and p doesn't name a villain, heroes if
+// it has HeroRotation set and environments if it has EnvRotation set and p
+// doesn't name an environment.  When fewer hero decks are left than there
+// are heroes to choose, those left are locked into the setup and the rest
+// come from the next round.
+func (pr *Profile) Rotate(p *Params) {
+	p.Group, p.Rotation, p.Bag = pr.Name, false, nil
+	if !pr.Rotation && !pr.HeroRotation && !pr.EnvRotation {
+		return
+	}
+	cs := p.CardSet()
+	var bag []string
+	if pr.Rotation && p.Villain == "" {
+		bag = append(bag, pr.bag(Villain, cs)...)
+	}
+	if pr.EnvRotation && p.Environment == "" {
+		bag = append(bag, pr.bag(Environment, cs)...)
+	}
+	if pr.HeroRotation {
+		left := pr.bag(Hero, cs)
+		decks := make(map[string]bool)
+		for _, n := range p.Heroes {
+			if c, ok := Lookup(n); ok {
+				decks[c.Base] = true
+			}
+		}
+		var spare []string // one hero of each deck left
+		for _, n := range left {
+			if c, _ := Lookup(n); !decks[c.Base] {
+				decks[c.Base] = true
+				spare = append(spare, n)
+			}
+		}
+		if len(spare) < p.Players-len(p.Heroes) {
+			p.Heroes = append(p.Heroes[:len(p.Heroes):len(p.Heroes)], spare...)
+		} else {
+			bag = append(append(bag, left...), p.Heroes...)
+		}
+	}
+	p.Rotation, p.Bag = true, bag
+}
+
+// ResetBag empties the named profile's rotation of cards of type t, so
+// that every card is back in it.
+func (ps *Profiles) ResetBag(name string, t CardType) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	pr := ps.ByName[name]
+	if pr == nil {
+		return fmt.Errorf("No profile named %q.", name)
+	}
+	c := *pr
+	*c.drawn(t) = nil
+	ps.ByName[name] = &c
+	return ps.save()
+}
+
+// rotate draws the cards of a setup generated from a group's rotations out
+// of the group's bags, putting back those of the setup it replaced, if
+// any.  A card drawn again once its round is over starts a new round.
+func (ps *Profiles) rotate(e Event) {
+	if e.Kind != Generated || e.Setup == nil || e.Setup.search == nil || !e.Setup.search.params.Rotation {
+		return
+	}
+	var vetoed []string
+	if e.Entry.VetoOf != "" {
+		if v := DefaultHistory.Entry(e.Entry.VetoOf); v != nil {
+			vetoed = append([]string{v.Villain, v.Environment}, v.Heroes...)
+		}
+	}
+	// the whole collection the setup was dealt from, not just the bags.
+	p := e.Setup.search.params
+	p.Bag = nil
+	cs := p.CardSet()
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	pr := ps.ByName[e.Entry.Group]
+	if pr == nil {
+		return
+	}
+	// copy the profile, since callers of Get may be reading it.
+	c := *pr
+	for _, t := range []CardType{Hero, Villain, Environment} {
+		if !c.rotates(t) || (t == Villain && p.Villain != "") || (t == Environment && p.Environment != "") {
+			continue
+		}
+		d := c.drawn(t)
+		var kept []string
+		for _, n := range *d {
+			if !inList(n, vetoed) {
+				kept = append(kept, n)
+			}
+		}
+		*d = kept
+		var cards []string
+		switch t {
+		case Hero:
+			cards = e.Entry.Heroes
+		case Villain:
+			cards = []string{e.Entry.Villain}
+		case Environment:
+			cards = []string{e.Entry.Environment}
+		}
+		// cards left from the last round first, so that it ends before
+		// the rest start the next.
+		var again []string
+		for _, n := range cards {
+			if inList(n, *d) {
+				again = append(again, n)
+			} else {
+				*d = append(*d, n)
+			}
+		}
+		for _, n := range again {
+			if len(c.undrawn(t, cs)) == 0 {
+				*d = nil
+			}
+			if !inList(n, *d) {
+				*d = append(*d, n)
+			}
+		}
+	}
+	ps.ByName[c.Name] = &c
+	if err := ps.save(); err != nil {
+		log.Printf("Couldn't save the rotations of %s: %v", c.Name, err)
+	}
+}
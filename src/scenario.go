@@ -0,0 +1,130 @@
+package sentinels
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// Scenario is a story arc: a sequence of games against fixed villains, each
+// introduced by narrative text, that a group plays through in order.
+// Scenarios are written as JSON, e.g.
+//
+//	{"name": "Blade's Revenge",
+//	 "intro": "Baron Blade has escaped from prison.",
+//	 "entries": [
+//		{"title": "The Jailbreak", "villain": "Baron Blade",
+//		 "environment": "Megalopolis", "text": "..."},
+//		{"title": "Mad Science", "villain": "Mad Bomber Blade",
+//		 "harder": 10, "text": "..."}]}
+type Scenario struct {
+	Name    string
+	Intro   string
+	Entries []ScenarioEntry
+}
+
+// ScenarioEntry is one game of a scenario.  Whatever it doesn't fix is
+// found to suit the group's target difficulty.
+type ScenarioEntry struct {
+	Title       string
+	Text        string   // narrative read before the game
+	Villain     string   // name of the villain, as in the data
+	Environment string   // name of the environment; if empty, one is found
+	Heroes      []string // heroes that must be played, e.g. the story's leads
+	Advanced    bool     // play the villain in advanced mode
+	// Harder is added to the group's target loss percentage, e.g. to make
+	// a finale harder than the games before it.
+	Harder int
+}
+
+// ParseScenario reads a scenario from JSON and checks that the cards it
+// names exist.
+func ParseScenario(b []byte) (*Scenario, error) {
+	sc := &Scenario{}
+	if err := json.Unmarshal(b, sc); err != nil {
+		return nil, err
+	}
+	if err := sc.check(); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// LoadScenario reads a scenario from a JSON file.
+func LoadScenario(path string) (*Scenario, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScenario(b)
+}
+
+// check checks that the scenario has entries and that their cards exist.
+func (sc *Scenario) check() error {
+	if err := EnsureData(); err != nil {
+		return err
+	}
+	if len(sc.Entries) == 0 {
+		return errors.New("The scenario has no entries.")
+	}
+	for i, e := range sc.Entries {
+		if c, ok := Lookup(e.Villain); !ok || c.Type != Villain {
+			return fmt.Errorf("Entry %d: unknown villain %q.", i+1, e.Villain)
+		}
+		if c, ok := Lookup(e.Environment); e.Environment != "" && (!ok || c.Type != Environment) {
+			return fmt.Errorf("Entry %d: unknown environment %q.", i+1, e.Environment)
+		}
+		for _, h := range e.Heroes {
+			if c, ok := Lookup(h); !ok || c.Type != Hero {
+				return fmt.Errorf("Entry %d: unknown hero %q.", i+1, h)
+			}
+		}
+	}
+	return nil
+}
+
+// Next returns the index of the entry the group plays next according to
+// the history: the group moves on by winning each entry's game in turn.
+// It returns len(sc.Entries) once the scenario is complete.
+func (sc *Scenario) Next(h *History) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	i := 0
+	for _, e := range h.Entries {
+		if i == len(sc.Entries) {
+			break
+		}
+		se := sc.Entries[i]
+		if e.Result == Won && e.Villain == se.Villain && (se.Environment == "" || e.Environment == se.Environment) {
+			i++
+		}
+	}
+	return i
+}
+
+// Play finds a setup for the i'th entry, counting from 0, at the group's
+// target difficulty.  The entry fixes the villain and may fix the
+// environment and some heroes; the other parameters come from p.  Harder
+// applies to a target loss percentage, not to a target total.
+func (sc *Scenario) Play(i int, p *Params) (*Setup, int, error) {
+	if i < 0 || i >= len(sc.Entries) {
+		return nil, 0, fmt.Errorf("The scenario has no entry %d.", i+1)
+	}
+	e := sc.Entries[i]
+	q := *p
+	q.Villain = e.Villain
+	q.Environment = e.Environment
+	q.Heroes = append(append([]string(nil), e.Heroes...), p.Heroes...)
+	if e.Advanced {
+		q.Advanced = true
+		q.AllowMissingAdvanced = true
+	}
+	q.LossPercent += e.Harder
+	if q.LossPercent < 1 {
+		q.LossPercent = 1
+	} else if q.LossPercent > 99 {
+		q.LossPercent = 99
+	}
+	return Find(&q)
+}
@@ -0,0 +1,52 @@
+package sentinels
+
+import (
+	"fmt"
+)
+
+// Score builds and scores the setup with the named cards, as if it had been
+// generated.  It is not recorded in the history.
+func Score(heroes []string, villain, env string, advanced bool) (*Setup, error) {
+	if err := EnsureData(); err != nil {
+		return nil, err
+	}
+	sd := loaded().sd
+	if len(heroes) < 3 || len(heroes) > len(sd.Difficulty.Nump)+2 {
+		return nil, fmt.Errorf("A setup needs 3 to %d heroes, not %d.", len(sd.Difficulty.Nump)+2, len(heroes))
+	}
+	card := func(name string, t CardType) (*Card, error) {
+		c, ok := Lookup(name)
+		if !ok || c.Type != t {
+			return nil, fmt.Errorf("Unknown card %q.", name)
+		}
+		return c, nil
+	}
+	s := &Setup{PcPoints: sd.Difficulty.Nump[len(heroes)-3].Points, Advanced: advanced}
+	bases := make(map[string]bool)
+	for _, n := range heroes {
+		c, err := card(n, Hero)
+		if err != nil {
+			return nil, err
+		}
+		if bases[c.Base] {
+			return nil, fmt.Errorf("%s shares a deck with another hero.", c.Name)
+		}
+		bases[c.Base] = true
+		s.Heroes = append(s.Heroes, c)
+	}
+	var err error
+	if s.Villain, err = card(villain, Villain); err != nil {
+		return nil, err
+	}
+	if s.Environment, err = card(env, Environment); err != nil {
+		return nil, err
+	}
+	s.Difficulty = Model.Score(s)
+	s.LossPercent = sd.LossPercentFor(s.Difficulty, len(heroes))
+	if Synergies != nil {
+		sy := Synergies.Synergy(s)
+		s.Synergy = &sy
+	}
+	s.warn()
+	return s, nil
+}
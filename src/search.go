@@ -0,0 +1,119 @@
+package sentinels
+
+import (
+	"sort"
+	"time"
+)
+
+// Unplayed is the HistoryQuery result matching setups with no recorded
+// result.
+const Unplayed = "unplayed"
+
+// HistoryQuery selects history entries.  Zero fields match every entry.
+type HistoryQuery struct {
+	Villain     string
+	Hero        string // a hero in the setup
+	Environment string
+	Group       string
+	From, To    time.Time // made at or after From and before To
+	Result      string    // Won, Lost or Unplayed
+	// MinLoss and MaxLoss bound the estimated loss percentage.
+	MinLoss, MaxLoss int
+	Vetoed           bool // include vetoed setups
+	Offset, Limit    int  // page of the matches to return; Limit 0 means all
+}
+
+// historyIndex maps "villain:", "hero:" and "env:" followed by a card's
+// CardKey to the positions of the entries naming it, in order.
+type historyIndex map[string][]int
+
+// add indexes the entry at position i, if the index has been built.
+func (ix historyIndex) add(i int, e *HistoryEntry) {
+	if ix == nil {
+		return
+	}
+	ix.put("villain:"+CardKey(e.Villain), i)
+	ix.put("env:"+CardKey(e.Environment), i)
+	for _, n := range e.Heroes {
+		ix.put("hero:"+CardKey(n), i)
+	}
+}
+
+// put appends position i to the list for key k.
+func (ix historyIndex) put(k string, i int) {
+	ix[k] = append(ix[k], i)
+}
+
+// Search returns copies of the entries matching q, newest first, and the
+// number of matches before q's page was taken.  Card names match as
+// CardKey compares them, e.g. "knyfe" finds K.N.Y.F.E.  They are looked up in
+// an index and dates by binary search, since entries are kept in the order
+// they were made, so that long histories stay quick to search.
+func (h *History) Search(q *HistoryQuery) ([]*HistoryEntry, int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.index == nil {
+		h.index = make(historyIndex)
+		for i, e := range h.Entries {
+			h.index.add(i, e)
+		}
+	}
+	lo, hi := 0, len(h.Entries)
+	if !q.From.IsZero() {
+		lo = sort.Search(len(h.Entries), func(i int) bool { return !h.Entries[i].Time.Before(q.From) })
+	}
+	if !q.To.IsZero() {
+		hi = sort.Search(len(h.Entries), func(i int) bool { return !h.Entries[i].Time.Before(q.To) })
+	}
+	// the positions to consider: the shortest index list, if any applies.
+	var cand []int
+	indexed := false
+	for _, k := range []string{"villain:" + CardKey(q.Villain), "hero:" + CardKey(q.Hero), "env:" + CardKey(q.Environment)} {
+		if k[len(k)-1] == ':' {
+			continue
+		}
+		if l := h.index[k]; !indexed || len(l) < len(cand) {
+			cand, indexed = l, true
+		}
+	}
+	if !indexed {
+		for i := lo; i < hi; i++ {
+			cand = append(cand, i)
+		}
+	}
+	var r []*HistoryEntry
+	total := 0
+	for j := len(cand) - 1; j >= 0; j-- {
+		i := cand[j]
+		if i < lo || i >= hi || !q.matches(h.Entries[i]) {
+			continue
+		}
+		if total >= q.Offset && (q.Limit == 0 || len(r) < q.Limit) {
+			c := *h.Entries[i]
+			r = append(r, &c)
+		}
+		total++
+	}
+	return r, total
+}
+
+// matches reports whether e meets all of q's conditions but its dates.
+func (q *HistoryQuery) matches(e *HistoryEntry) bool {
+	switch {
+	case e.Vetoed && !q.Vetoed,
+		q.Villain != "" && !sameCard(e.Villain, q.Villain),
+		q.Environment != "" && !sameCard(e.Environment, q.Environment),
+		q.Hero != "" && !namesCard(e.Heroes, q.Hero),
+		q.Group != "" && e.Group != q.Group,
+		q.MinLoss > 0 && e.LossPercent < q.MinLoss,
+		q.MaxLoss > 0 && e.LossPercent > q.MaxLoss:
+		return false
+	}
+	switch q.Result {
+	case "":
+		return true
+	case Unplayed:
+		return e.Result == ""
+	}
+	return e.Result == q.Result
+}
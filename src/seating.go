@@ -0,0 +1,41 @@
+package sentinels
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Seat assigns a player to a hero.
+type Seat struct {
+	Player string
+	Hero   *Card
+}
+
+// AssignSeats randomly assigns the players to the setup's heroes and sets a
+// random turn order, recorded as s.Seating with the first player first.
+// Missing names are filled in as "Player 1", "Player 2" and so on; extra
+// names are ignored.
+func (s *Setup) AssignSeats(players []string) {
+	s.Seating = make([]Seat, len(s.Heroes))
+	heroes := randPerm(len(s.Heroes))
+	order := randPerm(len(s.Heroes))
+	for i := range s.Heroes {
+		name := fmt.Sprintf("Player %d", i+1)
+		if i < len(players) {
+			if n := strings.TrimSpace(players[i]); n != "" {
+				name = n
+			}
+		}
+		s.Seating[order[i]] = Seat{Player: name, Hero: s.Heroes[heroes[i]]}
+	}
+}
+
+// SeatingText formats the seating as one line per player in turn order.
+func (s *Setup) SeatingText() string {
+	var b bytes.Buffer
+	for i, st := range s.Seating {
+		fmt.Fprintf(&b, "%d. %s plays %s\n", i+1, st.Player, st.Hero.DisplayName(s.Lang))
+	}
+	return b.String()
+}
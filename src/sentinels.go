@@ -0,0 +1,1769 @@
+package sentinels
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	sdBytes = []byte(sdJson)
+	current atomic.Pointer[dataSet] // nil until data is loaded
+
+	dataOnce sync.Once
+	dataErr  error
+)
+
+// dataSet is the card and scale data in use.  Loading data replaces the
+// whole set, so that a search or request which takes it once sees the same
+// data throughout, even if other data is loaded meanwhile.
+type dataSet struct {
+	sd      *SentinelsData
+	cards   map[string]*Card // keyed by CardKey
+	version string
+	time    time.Time // when the data was loaded
+}
+
+// useData makes sd, read from data and overlays, the data in use.
+func useData(sd *SentinelsData, data []byte, overlays ...[]byte) {
+	current.Store(&dataSet{sd: sd, cards: cardMap(sd), version: dataVersion(data, overlays...), time: Now()})
+}
+
+// loaded returns the data in use, which is empty if none has been loaded.
+func loaded() *dataSet {
+	if ds := current.Load(); ds != nil {
+		return ds
+	}
+	return &dataSet{}
+}
+
+// EnsureData parses the built-in card and scale data unless it or other data
+// has already been loaded, and returns any error from doing so.  Functions
+// that need the data call it themselves; call it first to pay the cost up
+// front or to see the error.  It is safe to call from several goroutines.
+func EnsureData() error {
+	dataOnce.Do(func() {
+		dataErr = parseSentinelsData()
+	})
+	if current.Load() != nil {
+		return nil
+	}
+	return dataErr
+}
+
+type CardType int
+
+const (
+	Hero CardType = iota
+	Villain
+	Environment
+)
+
+type ExpansionType int
+
+const (
+	BaseSet ExpansionType = iota
+	MiniExpansion
+	RookCity
+	InfernalRelics
+	ShatteredTimelines
+	Vengeance
+	Promos
+)
+
+// ExpansionNames are the short names of the expansions, indexed by
+// ExpansionType.
+var ExpansionNames = []string{"baseset", "miniexpansion", "rookcity", "infernalrelics", "shatteredtimelines", "vengeance", "promos"}
+
+// ExpansionTitles are the display names of the expansions, indexed by
+// ExpansionType.
+var ExpansionTitles = []string{"Base Set", "Mini-Expansions", "Rook City", "Infernal Relics", "Shattered Timelines", "Vengeance", "Promo cards"}
+
+// Title returns the expansion's display name.
+func (e ExpansionType) Title() string {
+	if e < 0 || int(e) >= len(ExpansionTitles) {
+		return e.String()
+	}
+	return ExpansionTitles[e]
+}
+
+// String returns the expansion's short name.
+func (e ExpansionType) String() string {
+	if e < 0 || int(e) >= len(ExpansionNames) {
+		return fmt.Sprintf("ExpansionType(%d)", int(e))
+	}
+	return ExpansionNames[e]
+}
+
+// ReleaseOrder lists the expansions in order of publication.  Promos were
+// released throughout and are not included.
+var ReleaseOrder = []ExpansionType{BaseSet, RookCity, InfernalRelics, MiniExpansion, ShatteredTimelines, Vengeance}
+
+// OwnedThrough returns the expansions released up to and including e, for
+// collectors who bought them in order.
+func OwnedThrough(e ExpansionType) []ExpansionType {
+	for i, r := range ReleaseOrder {
+		if r == e {
+			return append([]ExpansionType(nil), ReleaseOrder[:i+1]...)
+		}
+	}
+	return []ExpansionType{e}
+}
+
+// MarshalText implements encoding.TextMarshaler, so that expansions are
+// stored by name.
+func (e ExpansionType) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (e *ExpansionType) UnmarshalText(b []byte) error {
+	v, err := ParseExpansion(string(b))
+	if err != nil {
+		return err
+	}
+	*e = v
+	return nil
+}
+
+// ParseExpansion returns the expansion with the given short name, title
+// or alias; see ExpansionAliases.
+func ParseExpansion(name string) (ExpansionType, error) {
+	exp, err := lookupExpansions(name)
+	if err != nil {
+		return 0, err
+	}
+	if len(exp) != 1 {
+		return 0, fmt.Errorf("%q is a bundle of several expansions.", name)
+	}
+	return exp[0], nil
+}
+
+// ParseExpansions parses a comma-separated list of expansion short names,
+// titles or aliases, or names of Bundles, which stand for the expansions
+// they hold.  Each expansion is listed once.
+func ParseExpansions(list string) ([]ExpansionType, error) {
+	var exp []ExpansionType
+	for _, n := range strings.Split(list, ",") {
+		if n = strings.TrimSpace(n); n == "" {
+			continue
+		}
+		es, err := lookupExpansions(n)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range es {
+			if !hasExpansion(exp, e) {
+				exp = append(exp, e)
+			}
+		}
+	}
+	return exp, nil
+}
+
+// ParseCopies parses a comma-separated list of expansion=count pairs, such
+// as "baseset=2", into the form of Params.Copies.
+func ParseCopies(list string) (map[ExpansionType]int, error) {
+	copies := make(map[ExpansionType]int)
+	for _, f := range strings.Split(list, ",") {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("Expected expansion=count, not %q.", f)
+		}
+		e, err := ParseExpansion(strings.TrimSpace(kv[0]))
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("Bad number of copies %q.", kv[1])
+		}
+		copies[e] = n
+	}
+	return copies, nil
+}
+
+// Card represents a SotM card.
+type Card struct {
+	Name      string // unique name
+	Type      CardType
+	Expansion ExpansionType
+	Points    int
+	Advanced  int
+	AdvCount  int
+	Base      string   // Name of original card (for some promo versions)
+	Tags      []string // content tags, e.g. "dark"
+	Pool      string   // environment style, e.g. "urban"
+	Minutes   int      // typical extra play time, for slow villains and environments
+	// Complexity rates how hard a hero is to play, from 1 (simple) to 3
+	// (intricate).  Variants share their base hero's rating.
+	Complexity int
+	Archetype  string // a hero's role, e.g. "support"; variants share their base's
+	Nemesis    string // base name of a hero's nemesis villain; variants share their base's
+	// ByPlayers adjusts a hero's points for some numbers of heroes, for
+	// heroes that get much stronger or weaker as the table grows.
+	ByPlayers map[int]int
+	// Phases are stages of a game against a villain that are much harder
+	// or easier than it is overall.  Variants don't share their base's.
+	Phases []Phase
+	Hidden bool // left out of card sets by an overlay
+}
+
+// PointsFor returns the card's points in a game with n heroes.
+func (c *Card) PointsFor(n int) int {
+	return c.Points + c.ByPlayers[n]
+}
+
+// HasAdvancedData reports whether the card is a villain with recorded
+// advanced-mode results.
+func (c *Card) HasAdvancedData() bool {
+	return c.Type == Villain && c.AdvCount > 0
+}
+
+// ConfidentSamples is the number of recorded advanced-mode games at which a
+// villain's advanced score is considered reliable.
+const ConfidentSamples = 50
+
+// Confidence weights a villain's advanced score by its sample count: 0 with
+// no data, 0.5 at ConfidentSamples games, and approaching 1 beyond.
+func (c *Card) Confidence() float64 {
+	if !c.HasAdvancedData() {
+		return 0
+	}
+	return float64(c.AdvCount) / float64(c.AdvCount+ConfidentSamples)
+}
+
+// HasTag reports whether the card carries any of the given tags.
+func (c *Card) HasTag(tags ...string) bool {
+	for _, t := range tags {
+		for _, ct := range c.Tags {
+			if t == ct {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CardKey reduces a card name to the form cards are keyed by: lower case,
+// without spaces or punctuation, so that "K.N.Y.F.E.", "Knyfe" and "knyfe"
+// are the same card.
+func CardKey(name string) string {
+	return expansionKey(name)
+}
+
+// Lookup returns the card with the given name, ignoring case, spaces and
+// punctuation.  It finds nothing until EnsureData or LoadData is called.
+func Lookup(name string) (*Card, bool) {
+	c, ok := loaded().cards[CardKey(name)]
+	return c, ok
+}
+
+// sameCard reports whether two names are of the same card.
+func sameCard(a, b string) bool {
+	return CardKey(a) == CardKey(b)
+}
+
+// namesCard reports whether list holds a name of the card called name.
+func namesCard(list []string, name string) bool {
+	for _, n := range list {
+		if sameCard(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CardSet is a set of cards matching the user's selection criteria.
+type CardSet struct {
+	Heroes       []*Card
+	Villains     []*Card
+	Environments []*Card
+}
+
+// SentinelsData holds all the data unmarshaled from JSON.
+//
+// Deprecated: its fields follow the JSON and change with it.  Read the
+// data in use with Heroes, Villains, Environments, Scale and
+// HeroCountPoints instead.
+type SentinelsData struct {
+	Difficulty DifficultyData
+	Scale      []ScaleData
+	// Scales are scales for single numbers of heroes, keyed by the number,
+	// for tables whose loss rate at a total differs from Scale's.  Numbers
+	// without one use Scale.
+	Scales    map[int][]ScaleData `json:"scales,omitempty"`
+	Conflicts []Conflict          `json:"conflicts,omitempty"`
+}
+
+// DifficultyData contains the contents of the "difficulty" field.
+type DifficultyData struct {
+	Hero    []Difficulty
+	Villain []Difficulty
+	Env     []Difficulty
+	Nump    []Difficulty
+}
+
+// Difficulty gives the difficulty for a given hero/villain/environment
+type Difficulty struct {
+	Name     string
+	Base     string
+	Points   int
+	Advanced int
+	AdvCount int
+	Promo    bool
+	Tags     []string
+	Pool     string
+	Minutes  int
+	// Complexity is 1-3 for heroes; variants may leave it 0 to use their
+	// base's.
+	Complexity int
+	Archetype  string
+	Nemesis    string // a hero's nemesis villain, by base name
+	// ByPlayers maps numbers of heroes to adjustments to a hero's points,
+	// e.g. "byplayers": {"5": -12}.
+	ByPlayers map[int]int
+	// Phases adjust a villain's points for stages of the game, e.g.
+	// "phases": [{"when": "flips", "points": 30}].
+	Phases []Phase
+	// Hidden cards are left out of card sets; see LoadLayers.
+	Hidden bool `json:",omitempty"`
+}
+
+// ScaleData is the expected loss percentage for a given difficulty.
+type ScaleData struct {
+	Total   int `json:"total"`
+	LossPct int `json:"losspct"`
+}
+
+func parseSentinelsData() error {
+	nsd := &SentinelsData{}
+	if err := json.Unmarshal(sdBytes, nsd); err != nil {
+		return fmt.Errorf("Couldn't parse the built-in card data: %v", err)
+	}
+	useData(nsd, sdBytes)
+	return nil
+}
+
+// DataVersion identifies the card and scale data in use: a hash of the data
+// and any custom cards loaded with it.
+func DataVersion() string {
+	EnsureData()
+	return loaded().version
+}
+
+// DataModified returns when the data in use was loaded, for caches.
+func DataModified() time.Time {
+	EnsureData()
+	return loaded().time
+}
+
+// dataVersion hashes data and custom cards into a short version string.
+func dataVersion(data []byte, overlays ...[]byte) string {
+	h := sha256.New()
+	h.Write(data)
+	for _, b := range overlays {
+		h.Write(b)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))[:12]
+}
+
+// cardMap makes the cards described by sd, keyed by CardKey.
+func cardMap(sd *SentinelsData) map[string]*Card {
+	makeCard := func(d Difficulty) *Card {
+		c := &Card{Name: d.Name, Base: d.Base, Points: d.Points, Advanced: d.Advanced, AdvCount: d.AdvCount, Tags: d.Tags, Pool: d.Pool, Minutes: d.Minutes, Complexity: d.Complexity, Archetype: d.Archetype, Nemesis: d.Nemesis, ByPlayers: d.ByPlayers, Phases: d.Phases, Hidden: d.Hidden}
+		if c.Base == "" {
+			c.Base = c.Name
+		}
+		return c
+	}
+	cards := make(map[string]*Card)
+	for _, d := range sd.Difficulty.Hero {
+		c := makeCard(d)
+		c.Type = Hero
+		cards[CardKey(d.Name)] = c
+	}
+	for _, d := range sd.Difficulty.Villain {
+		c := makeCard(d)
+		c.Type = Villain
+		cards[CardKey(d.Name)] = c
+	}
+	for _, d := range sd.Difficulty.Env {
+		c := makeCard(d)
+		c.Type = Environment
+		cards[CardKey(d.Name)] = c
+	}
+	for _, c := range cards {
+		if b, ok := cards[CardKey(c.Base)]; ok {
+			if c.Complexity == 0 {
+				c.Complexity = b.Complexity
+			}
+			if c.Archetype == "" {
+				c.Archetype = b.Archetype
+			}
+			if c.Nemesis == "" {
+				c.Nemesis = b.Nemesis
+			}
+		}
+	}
+	for exp, names := range ExpansionCards {
+		for _, name := range names {
+			if c, ok := cards[CardKey(name)]; ok {
+				c.Expansion = exp
+			} else {
+				log.Printf("Couldn't find card %s while setting expansions.", name)
+			}
+		}
+	}
+	return cards
+}
+
+// GetCardSetByType builds a CardSet from the heroes, villains and
+// environments of separately selected expansions, e.g. to use an
+// expansion's villains and environments but not its heroes.
+func GetCardSetByType(heroes, villains, envs []ExpansionType) *CardSet {
+	EnsureData()
+	cs := new(CardSet)
+	for _, c := range loaded().cards {
+		switch {
+		case c.Hidden:
+		case c.Type == Hero && hasExpansion(heroes, c.Expansion):
+			cs.Heroes = append(cs.Heroes, c)
+		case c.Type == Villain && hasExpansion(villains, c.Expansion):
+			cs.Villains = append(cs.Villains, c)
+		case c.Type == Environment && hasExpansion(envs, c.Expansion):
+			cs.Environments = append(cs.Environments, c)
+		}
+	}
+	// sort so that a given seed always produces the same setup.
+	for _, l := range [][]*Card{cs.Heroes, cs.Villains, cs.Environments} {
+		sort.Sort(byName(l))
+	}
+	return cs
+}
+
+// hasExpansion reports whether e is in exp.
+func hasExpansion(exp []ExpansionType, e ExpansionType) bool {
+	for _, x := range exp {
+		if x == e {
+			return true
+		}
+	}
+	return false
+}
+
+// byName sorts cards by name.
+type byName []*Card
+
+func (b byName) Len() int           { return len(b) }
+func (b byName) Less(i, j int) bool { return b[i].Name < b[j].Name }
+func (b byName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+// WithoutTags returns a copy of the card set omitting cards that carry any
+// of the given tags.
+func (cs *CardSet) WithoutTags(tags ...string) *CardSet {
+	if len(tags) == 0 {
+		return cs
+	}
+	return cs.filter(func(c *Card) bool { return !c.HasTag(tags...) })
+}
+
+// Without returns a copy of the card set omitting the named cards.
+func (cs *CardSet) Without(names ...string) *CardSet {
+	if len(names) == 0 {
+		return cs
+	}
+	omit := make(map[string]bool)
+	for _, n := range names {
+		omit[CardKey(n)] = true
+	}
+	return cs.filter(func(c *Card) bool { return !omit[CardKey(c.Name)] })
+}
+
+// WithPromos returns a copy of the card set whose promo cards are limited
+// to the named ones, for collections holding only some of the promos.
+// With no names, the card set is returned unchanged.
+func (cs *CardSet) WithPromos(names ...string) *CardSet {
+	if len(names) == 0 {
+		return cs
+	}
+	keep := make(map[string]bool)
+	for _, n := range names {
+		keep[CardKey(n)] = true
+	}
+	return cs.filter(func(c *Card) bool { return c.Expansion != Promos || keep[CardKey(c.Name)] })
+}
+
+// InPools returns a copy of the card set whose environments are limited to
+// the given pools.  With no pools, the card set is returned unchanged.
+func (cs *CardSet) InPools(pools ...string) *CardSet {
+	if len(pools) == 0 {
+		return cs
+	}
+	return cs.filter(func(c *Card) bool { return c.Type != Environment || inList(c.Pool, pools) })
+}
+
+// WithoutPools returns a copy of the card set omitting environments in any
+// of the given pools.
+func (cs *CardSet) WithoutPools(pools ...string) *CardSet {
+	if len(pools) == 0 {
+		return cs
+	}
+	return cs.filter(func(c *Card) bool { return c.Type != Environment || !inList(c.Pool, pools) })
+}
+
+// EnvironmentPools returns the names of all environment pools, sorted.
+func EnvironmentPools() []string {
+	EnsureData()
+	seen := make(map[string]bool)
+	var pools []string
+	for _, c := range loaded().cards {
+		if c.Type == Environment && c.Pool != "" && !seen[c.Pool] {
+			seen[c.Pool] = true
+			pools = append(pools, c.Pool)
+		}
+	}
+	sort.Strings(pools)
+	return pools
+}
+
+// inList reports whether s is one of list.
+func inList(s string, list []string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns a copy of the card set containing the cards for which keep
+// returns true.
+func (cs *CardSet) filter(keep func(*Card) bool) *CardSet {
+	f := func(cards []*Card) []*Card {
+		var r []*Card
+		for _, c := range cards {
+			if keep(c) {
+				r = append(r, c)
+			}
+		}
+		return r
+	}
+	return &CardSet{
+		Heroes:       f(cs.Heroes),
+		Villains:     f(cs.Villains),
+		Environments: f(cs.Environments),
+	}
+}
+
+// String formats a CardSet for output.
+func (cs *CardSet) String() string {
+	var b bytes.Buffer
+	fmt.Fprint(&b, "Heroes:\n")
+	for _, c := range cs.Heroes {
+		fmt.Fprintf(&b, "   %s\n", c.Name)
+	}
+	fmt.Fprint(&b, "Villains:\n")
+	for _, c := range cs.Villains {
+		fmt.Fprintf(&b, "   %s\n", c.Name)
+	}
+	fmt.Fprint(&b, "Environments:\n")
+	for _, c := range cs.Environments {
+		fmt.Fprintf(&b, "   %s\n", c.Name)
+	}
+	return b.String()
+}
+
+// Setup is a specific game setup.
+type Setup struct {
+	Heroes      []*Card
+	Villain     *Card
+	Environment *Card
+	PcPoints    int
+	LossPercent int
+	Difficulty  int
+	Lang        string // language for card names; see DisplayName
+	Token       string // identifies the setup for Veto
+	VetoesLeft  int    // number of times the setup may still be vetoed
+	VetoOf      string // token of the setup this one replaced, if any
+	Seating     []Seat // players in turn order; see AssignSeats
+	Seed        *SeedRecord
+	Approximate bool // no setup in the target range was found; this is the closest
+	Advanced    bool // the villain is played in advanced mode
+	Warnings    []Warning
+	Synergy     *Synergy     `json:",omitempty"` // the team's synergy, if Synergies is set
+	Stats       *SearchStats // what the search saw on the way to this setup
+	// Notes and HouseRules are what the players noted about the game; see
+	// Annotate.
+	Notes      string   `json:",omitempty"`
+	HouseRules []string `json:",omitempty"`
+	search     *search
+}
+
+// SearchStats describes the candidate setups a search generated.
+type SearchStats struct {
+	Candidates int // scored candidates
+	Excluded   int // candidates skipped because they had been vetoed
+	Unseatable int // candidates skipped because the team wouldn't play them
+	Nemesis    int // candidates skipped because a hero faced their nemesis
+	Conflicts  int // candidates skipped because the villain and environment conflict
+	Style      int // candidates skipped because their points didn't fit the style
+	Synergy    int // candidates skipped because their team's synergy was too low
+	Min, Max   int // lowest and highest candidate difficulty
+	Sum        int // total candidate difficulty, for the mean
+}
+
+// add counts a candidate of the given difficulty.
+func (st *SearchStats) add(d int) {
+	if st.Candidates == 0 || d < st.Min {
+		st.Min = d
+	}
+	if st.Candidates == 0 || d > st.Max {
+		st.Max = d
+	}
+	st.Candidates++
+	st.Sum += d
+}
+
+// Mean returns the mean candidate difficulty.
+func (st *SearchStats) Mean() float64 {
+	if st.Candidates == 0 {
+		return 0
+	}
+	return float64(st.Sum) / float64(st.Candidates)
+}
+
+// VillainPoints returns the villain's difficulty points, using the advanced
+// score, weighted by its Confidence, in advanced mode when there is data for
+// it.
+func (s *Setup) VillainPoints() int {
+	return villainScore(s.Villain, s.Advanced)
+}
+
+// Key identifies the combination of cards in a setup, regardless of hero
+// order.
+func (s *Setup) Key() string {
+	names := make([]string, 0, len(s.Heroes)+2)
+	for _, h := range s.Heroes {
+		names = append(names, h.Name)
+	}
+	sort.Strings(names)
+	names = append(names, s.Villain.Name, s.Environment.Name)
+	return strings.Join(names, "|")
+}
+
+// String formats a setup for logging.
+func (s *Setup) String() string {
+	heroes := make([]string, len(s.Heroes))
+	for i, h := range s.Heroes {
+		heroes[i] = fmt.Sprintf("%s[%d]", h.DisplayName(s.Lang), s.HeroPoints(h))
+	}
+	return fmt.Sprintf(
+		"%s; %s[%d]; %s[%d]; %d heroes[%d]; difficulty=%d",
+		strings.Join(heroes, ", "),
+		s.villainName(),
+		s.VillainPoints(),
+		s.Environment.DisplayName(s.Lang),
+		s.Environment.Points,
+		len(heroes),
+		s.PcPoints,
+		s.Difficulty)
+}
+
+// HeroPoints returns a hero's points at the setup's number of heroes.
+func (s *Setup) HeroPoints(h *Card) int {
+	return h.PointsFor(len(s.Heroes))
+}
+
+// villainName returns the villain's display name, marked if advanced.
+func (s *Setup) villainName() string {
+	if s.Advanced {
+		return s.Villain.DisplayName(s.Lang) + " (advanced)"
+	}
+	return s.Villain.DisplayName(s.Lang)
+}
+
+// PlainText formats a setup as punctuation-light text suitable for screen
+// readers, one item per line.
+func (s *Setup) PlainText() string {
+	var b bytes.Buffer
+	for _, h := range s.Heroes {
+		fmt.Fprintf(&b, "Hero: %s, %s\n", h.DisplayName(s.Lang), SpokenPoints(s.HeroPoints(h)))
+	}
+	fmt.Fprintf(&b, "Villain: %s, %s\n", s.villainName(), SpokenPoints(s.VillainPoints()))
+	fmt.Fprintf(&b, "Environment: %s, %s\n", s.Environment.DisplayName(s.Lang), SpokenPoints(s.Environment.Points))
+	fmt.Fprintf(&b, "Number of heroes: %d, %s\n", len(s.Heroes), SpokenPoints(s.PcPoints))
+	fmt.Fprintf(&b, "Total difficulty: %s\n", SpokenPoints(s.Difficulty))
+	fmt.Fprintf(&b, "Expected loss: %d percent\n", s.LossPercent)
+	return b.String()
+}
+
+// SpokenPoints formats a point value in words a screen reader reads cleanly,
+// e.g. "minus 6 points".
+func SpokenPoints(n int) string {
+	switch {
+	case n < 0:
+		return fmt.Sprintf("minus %d points", -n)
+	case n == 1:
+		return "1 point"
+	}
+	return fmt.Sprintf("%d points", n)
+}
+
+// makeSetup generates a random setup from the search's card set, including
+// its locked heroes, and scores its difficulty.
+func (q *search) makeSetup(pcpts int) (*Setup, error) {
+	cs, locked := q.cs, q.heroes
+	n := q.pc - len(locked)
+	repeats := len(q.params.Copies) > 0
+	if n > len(cs.Heroes) && !repeats {
+		return nil, errors.New("Too many players for the selected heroes.")
+	}
+	if len(cs.Villains) == 0 {
+		return nil, errors.New("No villains in the selected card set.")
+	}
+	if len(cs.Environments) == 0 {
+		return nil, errors.New("No environments in the selected card set.")
+	}
+	s := &Setup{PcPoints: pcpts, LossPercent: q.lp, Advanced: q.advanced}
+	for {
+		s.Heroes = append([]*Card(nil), locked...)
+		bases := make(map[string]int)
+		for _, c := range locked {
+			bases[c.Base]++
+		}
+		if n == 0 {
+			break
+		}
+		var picked []int
+		if repeats {
+			// a hero may be picked again while its base has decks left, so
+			// draw with replacement and let the check below reject the
+			// rest; every hero stays equally likely to be drawn.
+			picked = make([]int, n)
+			for i := range picked {
+				picked[i] = q.rng.Intn(len(cs.Heroes))
+			}
+		} else {
+			var err error
+			if picked, err = pick(q.rng.Intn, len(cs.Heroes), n); err != nil {
+				return nil, err
+			}
+			if q.rngKind == LegacyRNG {
+				// records made with it shuffled all the heroes; draw
+				// what that drew, so they replay.
+				for i := n; i < len(cs.Heroes); i++ {
+					q.rng.Intn(len(cs.Heroes) - i)
+				}
+			}
+		}
+		for _, i := range picked {
+			c := cs.Heroes[i]
+			// if we have more heroes with the same base than there are
+			// decks for them, try again.
+			if bases[c.Base] >= q.params.copies(c) {
+				s.Heroes = nil
+				break
+			}
+			bases[c.Base]++
+			s.Heroes = append(s.Heroes, c)
+		}
+		// keep trying until we get a list with no duplicate bases.
+		if s.Heroes != nil {
+			break
+		}
+	}
+	s.Villain = cs.Villains[q.rng.Intn(len(cs.Villains))]
+	s.Environment = cs.Environments[q.rng.Intn(len(cs.Environments))]
+	s.Difficulty = Model.Score(s)
+	return s, nil
+}
+
+// heroDecks returns the number of heroes that can be added to the locked
+// ones: one for each deck of each base in the card set, since a base's
+// variants share its deck.  Without this check, makeSetup would retry
+// forever when the heroes can't be dealt, so run checks it first.
+func (q *search) heroDecks() int {
+	decks := make(map[string]int)
+	for _, c := range q.cs.Heroes {
+		if _, ok := decks[c.Base]; !ok {
+			decks[c.Base] = q.params.copies(c)
+		}
+	}
+	for _, c := range q.heroes {
+		decks[c.Base]--
+	}
+	n := 0
+	for _, d := range decks {
+		if d > 0 {
+			n += d
+		}
+	}
+	return n
+}
+
+// Params are the parameters of a setup search.
+type Params struct {
+	Players     int
+	LossPercent int  // target loss percentage, unless ByTotal is set
+	Range       int  // allowable difficulty variance around LossPercent
+	ByTotal     bool // target TargetTotal instead of LossPercent
+	TargetTotal int
+	Tolerance   int // allowable difficulty variance around TargetTotal
+	Expansions  []ExpansionType
+	ExcludeTags []string // cards with any of these tags are not used
+	Exclude     []string // names of cards not to use
+	// ExcludeVariants also leaves out cards sharing a deck with an
+	// excluded card, such as Mad Bomber Blade when Baron Blade is excluded.
+	ExcludeVariants bool
+	Heroes          []string // names of heroes that must be in the setup
+	Pools           []string // if set, only environments in these pools are used
+	AvoidPools      []string // environments in these pools are not used
+	Advanced        bool     // play the villain in advanced mode
+	// AllowMissingAdvanced allows villains with no advanced-mode data in
+	// advanced mode, scored at their normal difficulty with a warning.
+	AllowMissingAdvanced bool
+	// HighConfidence leaves out villains with fewer than ConfidentSamples
+	// recorded advanced-mode games in advanced mode.
+	HighConfidence bool
+	Tier           Tier          // if set, only villains in this tier are used
+	Seed           int64         // seed for the random numbers; 0 picks one at random
+	MaxIterations  int           // setups to try; 0 means DefaultMaxIterations
+	MaxDuration    time.Duration // time to search; 0 means no limit
+	// Team, if set, seats these players, each at a hero they will play.
+	Team []*Player `json:",omitempty"`
+	// Fair rotates the team's hero archetypes: players are steered away
+	// from the kinds of hero they played in their recent games.
+	Fair bool
+	// HeroExpansions, VillainExpansions and EnvExpansions, if set, replace
+	// Expansions for cards of that type.
+	HeroExpansions    []ExpansionType `json:",omitempty"`
+	VillainExpansions []ExpansionType `json:",omitempty"`
+	EnvExpansions     []ExpansionType `json:",omitempty"`
+	// Copies is the number of copies owned of each expansion; those not
+	// listed count as one.  A base hero can be played by as many players
+	// as there are copies of its deck.
+	Copies map[ExpansionType]int `json:",omitempty"`
+	// AllowConflicts allows villain and environment pairings listed in the
+	// data's conflicts, with a warning giving the reason.
+	AllowConflicts bool
+	// Nemesis prefers or avoids setups in which a hero faces their nemesis.
+	Nemesis NemesisMode
+	// Style, if set, says how the target is to be reached: by a strong
+	// team against a hard villain or a weak one against an easy villain.
+	Style Style
+	// MinSynergy is the lowest team synergy accepted when Synergies is
+	// set; the default, 0, passes over teams with negative synergy.
+	MinSynergy int `json:",omitempty"`
+	// Villain and Environment, if set, name the only villain and
+	// environment used, e.g. for a scenario's entry.
+	Villain     string `json:",omitempty"`
+	Environment string `json:",omitempty"`
+	// Group is the profile of the group the setup is for; see
+	// Profile.Leaderboard.
+	Group string `json:",omitempty"`
+	// Bag, if set, limits the cards of each type it names any of to
+	// those it names.  Rotation marks the search as dealt from the group's
+	// rotations, so that the cards drawn are taken out of them; see
+	// Profile.Rotate.
+	Bag      []string `json:",omitempty"`
+	Rotation bool     `json:",omitempty"`
+	// Promos, if set, are the only promo cards used, whether or not
+	// Expansions lists Promos.
+	Promos []string `json:",omitempty"`
+	// Teaching limits the cards to those suited to new players and caps
+	// LossPercent at TeachingLossPercent.
+	Teaching bool `json:",omitempty"`
+	// Progress, if set, is called every ProgressInterval iterations of
+	// the search, e.g. to show how a long search is going.
+	Progress func(Progress) `json:"-"`
+}
+
+// Progress describes how far a search has got.
+type Progress struct {
+	Iterations int // setups tried so far
+	Max        int // setups the search will try before giving up
+	// Distance is how many points the closest setup found so far is from
+	// the target range, or -1 if none has been found.
+	Distance int
+}
+
+// ProgressInterval is the number of iterations between calls to
+// Params.Progress.
+const ProgressInterval = 1000
+
+// DefaultMaxIterations is the number of setups tried before giving up.
+const DefaultMaxIterations = 100000
+
+// Find finds a setup matching the given parameters.
+func Find(p *Params) (*Setup, int, error) {
+	return find(context.Background(), p)
+}
+
+// find is Find, giving up when ctx is done.
+func find(ctx context.Context, p *Params) (*Setup, int, error) {
+	log.Printf("params: %+v", *p)
+	q, err := newSearchFor(p)
+	if err != nil {
+		return nil, 0, err
+	}
+	// vetoes rerun the search, but not for whoever watched this one.
+	q.ctx = ctx
+	defer func() { q.progress, q.ctx = nil, nil }()
+	return q.issue(DefaultVetoes, "")
+}
+
+// newSearchFor prepares a search for the given parameters.
+func newSearchFor(p *Params) (*search, error) {
+	if err := EnsureData(); err != nil {
+		return nil, err
+	}
+	ds := loaded()
+	sd := ds.sd
+	if p.Players < 3 || p.Players > len(sd.Difficulty.Nump)+2 {
+		return nil, fmt.Errorf("A setup needs 3 to %d heroes, not %d.", len(sd.Difficulty.Nump)+2, p.Players)
+	}
+	heroes, err := lockedHeroes(p)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range p.Promos {
+		if c, ok := Lookup(n); !ok || c.Expansion != Promos {
+			return nil, fmt.Errorf("%q isn't a promo card.", n)
+		}
+	}
+	cs := p.CardSet()
+	if p.Villain != "" && len(cs.Villains) == 0 {
+		return nil, fmt.Errorf("The villain %s isn't among the cards chosen.", p.Villain)
+	}
+	if p.Environment != "" && len(cs.Environments) == 0 {
+		return nil, fmt.Errorf("The environment %s isn't among the cards chosen.", p.Environment)
+	}
+	var q *search
+	if p.ByTotal {
+		tt := p.TargetTotal
+		q = newSearch(cs, p.Players, sd.LossPercentFor(tt, p.Players), tt-p.Tolerance, tt+p.Tolerance)
+	} else {
+		lp, min, max := p.lossRange(sd)
+		q = newSearch(cs, p.Players, lp, min, max)
+	}
+	q.data = ds
+	q.heroes = heroes
+	q.advanced = p.Advanced
+	q.params = *p
+	q.params.Progress = nil // not kept in seed records
+	q.progress = p.Progress
+	if p.Fair {
+		q.params.Team = DefaultHistory.recentTeam(p.Team)
+	}
+	q.nemesis = p.Nemesis
+	if q.nemesis == PreferNemesis && !nemesisPossible(q.cs) {
+		q.nemesis = AnyNemesis
+	}
+	return q, nil
+}
+
+// CardSet returns the cards selected by the parameters.
+func (p *Params) CardSet() *CardSet {
+	bagged := make(map[CardType]bool)
+	for _, n := range p.Bag {
+		if c, ok := Lookup(n); ok {
+			bagged[c.Type] = true
+		}
+	}
+	cs := GetCardSetByType(p.expansions(Hero), p.expansions(Villain), p.expansions(Environment)).
+		WithoutTags(p.ExcludeTags...).
+		Without(p.excluded()...).
+		WithPromos(p.Promos...).
+		InPools(p.Pools...).
+		WithoutPools(p.AvoidPools...).
+		filter(func(c *Card) bool {
+			if c.Type == Villain && p.Tier != AnyTier && VillainTier(c, p.Advanced) != p.Tier {
+				return false
+			}
+			if c.Type == Villain && p.Villain != "" && !sameCard(c.Name, p.Villain) {
+				return false
+			}
+			if bagged[c.Type] && !inList(c.Name, p.Bag) {
+				return false
+			}
+			if c.Type == Environment && p.Environment != "" && !sameCard(c.Name, p.Environment) {
+				return false
+			}
+			if !p.Advanced || c.Type != Villain {
+				return true
+			}
+			if p.HighConfidence {
+				return c.AdvCount >= ConfidentSamples
+			}
+			return p.AllowMissingAdvanced || c.HasAdvancedData()
+		})
+	if p.Teaching {
+		cs = cs.teaching(p.Players)
+	}
+	return cs
+}
+
+// expansions returns the expansions cards of type t are drawn from.
+func (p *Params) expansions(t CardType) []ExpansionType {
+	var exp []ExpansionType
+	switch t {
+	case Hero:
+		exp = p.HeroExpansions
+	case Villain:
+		exp = p.VillainExpansions
+	case Environment:
+		exp = p.EnvExpansions
+	}
+	if exp == nil {
+		exp = p.Expansions
+	}
+	if len(p.Promos) > 0 && !hasExpansion(exp, Promos) {
+		exp = append(exp[:len(exp):len(exp)], Promos)
+	}
+	return exp
+}
+
+// copies returns the number of copies owned of the deck a card is played
+// with.
+func (p *Params) copies(c *Card) int {
+	deck := c
+	if b, ok := Lookup(c.Base); ok {
+		deck = b
+	}
+	if n := p.Copies[deck.Expansion]; n > 1 {
+		return n
+	}
+	return 1
+}
+
+// lossRange returns the loss percentage targeted when ByTotal isn't set
+// and the range of difficulty totals accepted for it on sd's scale.  A
+// teaching game targets at most TeachingLossPercent and accepts any easier
+// setup.
+func (p *Params) lossRange(sd *SentinelsData) (lp, min, max int) {
+	lp = p.LossPercent
+	if p.Teaching && lp > TeachingLossPercent {
+		lp = TeachingLossPercent
+	}
+	min, max = sd.DifficultyRangeFor(lp, p.Players)
+	if p.Teaching {
+		min, _ = sd.DifficultyRangeFor(0, p.Players)
+	}
+	return lp, min - p.Range, max + p.Range
+}
+
+// excluded returns the names of the cards to leave out: p.Exclude, plus,
+// if p.ExcludeVariants is set, every card sharing a deck with one of them.
+func (p *Params) excluded() []string {
+	if !p.ExcludeVariants {
+		return p.Exclude
+	}
+	return Variants(p.Exclude...)
+}
+
+// Variants returns the named cards together with every card sharing a base
+// (and so a physical deck) with one of them, e.g. Baron Blade and Mad Bomber
+// Blade.  Unknown names are returned unchanged.
+func Variants(names ...string) []string {
+	bases := make(map[string]bool)
+	var r []string
+	for _, n := range names {
+		if c, ok := Lookup(n); ok {
+			bases[c.Base] = true
+		} else {
+			r = append(r, n)
+		}
+	}
+	for _, c := range loaded().cards {
+		if bases[c.Base] {
+			r = append(r, c.Name)
+		}
+	}
+	sort.Strings(r)
+	return r
+}
+
+// lockedHeroes looks up the heroes named in p.Heroes.
+func lockedHeroes(p *Params) ([]*Card, error) {
+	if len(p.Heroes) > p.Players {
+		return nil, errors.New("More heroes chosen than there are players.")
+	}
+	var heroes []*Card
+	bases := make(map[string]int)
+	for _, n := range p.Heroes {
+		c, ok := Lookup(n)
+		if !ok || c.Type != Hero {
+			return nil, fmt.Errorf("Unknown hero %q.", n)
+		}
+		if bases[c.Base] >= p.copies(c) {
+			return nil, fmt.Errorf("More versions of %s chosen than there are decks for.", c.Base)
+		}
+		bases[c.Base]++
+		heroes = append(heroes, c)
+	}
+	return heroes, nil
+}
+
+// search holds the parameters of a setup search so that it can be rerun.
+type search struct {
+	data     *dataSet // the data in use when the search was made
+	cs       *CardSet
+	pc, lp   int
+	min, max int
+	heroes   []*Card         // heroes that must be in the setup
+	advanced bool            // play the villain in advanced mode
+	exclude  map[string]bool // keys of setups that may not be returned
+	params   Params          // the parameters the search was made from
+	rng      searchRand      // source of the current run's random numbers
+	rngKind  string          // generator runs use; see PCGRNG
+	nemesis  NemesisMode     // p.Nemesis, or AnyNemesis if no matchup is possible
+	progress func(Progress)  // p.Progress
+	ctx      context.Context // cancels the search, if set
+}
+
+func newSearch(cs *CardSet, pc, lp, min, max int) *search {
+	return &search{cs: cs, pc: pc, lp: lp, min: min, max: max, exclude: make(map[string]bool), rngKind: PCGRNG}
+}
+
+// run generates setups using random numbers from seed until one has a
+// difficulty between min and max.  The setup's Seed records how to
+// reproduce it.  If the search's budget runs out first, the closest setup
+// found is returned, marked Approximate.
+func (q *search) run(seed int64) (*Setup, int, error) {
+	if q.pc-len(q.heroes) > q.heroDecks() {
+		return nil, 0, errors.New("Too many players for the selected heroes.")
+	}
+	var draws func() int
+	var err error
+	if q.rng, draws, err = newSearchRand(q.rngKind, seed); err != nil {
+		return nil, 0, err
+	}
+	pcpts := q.data.sd.Difficulty.Nump[q.pc-3].Points
+	maxIter := q.params.MaxIterations
+	if maxIter <= 0 {
+		maxIter = DefaultMaxIterations
+	}
+	var deadline time.Time
+	if q.params.MaxDuration > 0 {
+		deadline = time.Now().Add(q.params.MaxDuration)
+	}
+	var best *Setup
+	bestDist := 0
+	st := &SearchStats{}
+	for i := 0; ; i++ {
+		if q.progress != nil && i > 0 && i%ProgressInterval == 0 {
+			pr := Progress{Iterations: i, Max: maxIter, Distance: -1}
+			if best != nil {
+				pr.Distance = bestDist
+			}
+			q.progress(pr)
+		}
+		if q.ctx != nil && i%ProgressInterval == 0 && q.ctx.Err() != nil {
+			return nil, i, q.ctx.Err()
+		}
+		if i >= maxIter || (!deadline.IsZero() && i%1000 == 0 && time.Now().After(deadline)) {
+			if best == nil {
+				return nil, i, errors.New("Couldn't find a setup with these parameters.")
+			}
+			if bestDist == 0 {
+				// only PreferNemesis passes over setups in range.
+				log.Printf("iterations: %d, setup without a nemesis: %s", i, best)
+				best.Stats = st
+				best.Warnings = append(best.Warnings, Warning{Kind: WarnNoNemesis, Message: "No setup with a hero facing their nemesis was found."})
+				return best, i, nil
+			}
+			log.Printf("iterations: %d, approximate setup: %s", i, best)
+			best.Stats = st
+			best.Approximate = true
+			best.Warnings = append(best.Warnings, Warning{Kind: WarnApproximate, Message: fmt.Sprintf(
+				"No setup in the target range was found in %d iterations; this is the closest, %d points away.", i, bestDist)})
+			return best, i, nil
+		}
+		s, err := q.makeSetup(pcpts)
+		if err != nil {
+			return nil, 0, err
+		}
+		key := s.Key()
+		if q.exclude[key] {
+			st.Excluded++
+			continue
+		}
+		if len(q.params.Team) > 0 {
+			if s.Seating = seatTeam(s.Heroes, q.params.Team, q.rng); s.Seating == nil {
+				st.Unseatable++
+				continue
+			}
+		}
+		if !q.params.AllowConflicts && len(s.Conflicts()) > 0 {
+			st.Conflicts++
+			continue
+		}
+		if q.nemesis == AvoidNemesis && len(s.Nemeses()) > 0 {
+			st.Nemesis++
+			continue
+		}
+		if !q.params.Style.Fits(s) {
+			st.Style++
+			continue
+		}
+		if Synergies != nil {
+			sy := Synergies.Synergy(s)
+			if sy.Points < q.params.MinSynergy {
+				st.Synergy++
+				continue
+			}
+			s.Synergy = &sy
+		}
+		st.add(s.Difficulty)
+		d := 0
+		if s.Difficulty < q.min {
+			d = q.min - s.Difficulty
+		} else if s.Difficulty > q.max {
+			d = s.Difficulty - q.max
+		}
+		if best == nil || d < bestDist {
+			s.search = q
+			s.Seed = q.record(seed, draws(), s)
+			best, bestDist = s, d
+		}
+		if d == 0 && (q.nemesis != PreferNemesis || len(s.Nemeses()) > 0) {
+			log.Printf("iterations: %d, setup: %s", i+1, s)
+			s.Stats = st
+			return s, i + 1, nil
+		}
+	}
+}
+
+// DifficultyRange returns the minimum and maximum difficulty totals for a
+// loss percentage, interpolating between scale entries when necessary.
+func DifficultyRange(lp int) (min, max int) {
+	return DifficultyRangeFor(lp, 0)
+}
+
+// DifficultyRangeFor is DifficultyRange for a game with the given number of
+// heroes, using the scale for that number if the data has one.
+func DifficultyRangeFor(lp, players int) (min, max int) {
+	if EnsureData() != nil {
+		return 0, 0
+	}
+	return loaded().sd.DifficultyRangeFor(lp, players)
+}
+
+// LossPercent returns the expected loss percentage for a difficulty total.
+func LossPercent(total int) int {
+	return LossPercentFor(total, 0)
+}
+
+// LossPercentFor is LossPercent for a game with the given number of heroes,
+// using the scale for that number if the data has one.
+func LossPercentFor(total, players int) int {
+	if EnsureData() != nil {
+		return 0
+	}
+	return loaded().sd.LossPercentFor(total, players)
+}
+
+// DifficultyRange returns the minimum and maximum difficulty totals for a
+// loss percentage.  Percentages outside the scale are clamped to its ends;
+// percentages with no exact entry are interpolated from their neighbors, in
+// which case min and max are equal.
+func (sd *SentinelsData) DifficultyRange(lp int) (min, max int) {
+	return sd.DifficultyRangeFor(lp, 0)
+}
+
+// DifficultyRangeFor is DifficultyRange using the scale for the given
+// number of heroes.
+func (sd *SentinelsData) DifficultyRangeFor(lp, players int) (min, max int) {
+	sc := sd.scaleFor(players)
+	if lp > sc[0].LossPct {
+		lp = sc[0].LossPct
+	}
+	if lp < sc[len(sc)-1].LossPct {
+		lp = sc[len(sc)-1].LossPct
+	}
+	found := false
+	for _, v := range sc {
+		if v.LossPct == lp {
+			if !found {
+				max = v.Total
+				found = true
+			}
+			min = v.Total
+		}
+	}
+	if found {
+		return
+	}
+	for i := 1; i < len(sc); i++ {
+		hi, lo := sc[i-1], sc[i]
+		if hi.LossPct > lp && lo.LossPct < lp {
+			t := interpolate(lp, lo.LossPct, hi.LossPct, lo.Total, hi.Total)
+			return t, t
+		}
+	}
+	return
+}
+
+// LossPercent returns the expected loss percentage for a difficulty total.
+// Totals outside the scale are clamped to its ends; totals between entries
+// are interpolated.
+func (sd *SentinelsData) LossPercent(total int) int {
+	return sd.LossPercentFor(total, 0)
+}
+
+// LossPercentFor is LossPercent using the scale for the given number of
+// heroes.
+func (sd *SentinelsData) LossPercentFor(total, players int) int {
+	sc := sd.scaleFor(players)
+	if total >= sc[0].Total {
+		return sc[0].LossPct
+	}
+	for i := 1; i < len(sc); i++ {
+		hi, lo := sc[i-1], sc[i]
+		if lo.Total <= total {
+			return interpolate(total, lo.Total, hi.Total, lo.LossPct, hi.LossPct)
+		}
+	}
+	return sc[len(sc)-1].LossPct
+}
+
+// scale returns the data's scale, repaired by RepairScale if it is out of
+// order.
+func (sd *SentinelsData) scale() []ScaleData {
+	return sd.scaleFor(0)
+}
+
+// scaleFor returns the data's scale for the given number of heroes, which
+// is Scale unless Scales has one, repaired by RepairScale if it is out of
+// order.
+func (sd *SentinelsData) scaleFor(players int) []ScaleData {
+	sc := sd.Scale
+	if s, ok := sd.Scales[players]; ok && len(s) >= 2 {
+		sc = s
+	}
+	if checkScale(sc) != nil {
+		return RepairScale(sc)
+	}
+	return sc
+}
+
+// interpolate maps x in [x0, x1] linearly onto [y0, y1], rounding to the
+// nearest integer.
+func interpolate(x, x0, x1, y0, y1 int) int {
+	if x1 == x0 {
+		return y0
+	}
+	f := float64(y0) + float64(x-x0)*float64(y1-y0)/float64(x1-x0)
+	return int(math.Floor(f + 0.5))
+}
+
+// pick picks m different random numbers between 0 and n-1, using intn
+// (such as rand.Intn) as the source of randomness.  It returns an error,
+// rather than panicking, if there aren't m numbers to pick.
+//
+// It is a partial Fisher-Yates shuffle, calling intn m times: if intn is
+// uniform, each of the n!/(n-m)! ordered choices is equally likely, and so
+// each number is picked with probability m/n.  TestPickUniform tests this.
+func pick(intn func(int) int, n, m int) ([]int, error) {
+	if n < 0 || m < 0 || m > n {
+		return nil, fmt.Errorf("Can't pick %d different numbers from %d.", m, n)
+	}
+	vals := make([]int, n)
+	for i := range vals {
+		vals[i] = i
+	}
+	for i := 0; i < m; i++ {
+		j := i + intn(n-i)
+		vals[i], vals[j] = vals[j], vals[i]
+	}
+	return vals[:m], nil
+}
+
+// original data at http://x.gray.org/sentinels.json
+// some names normalized (e.g. "Silver Gulch, 1883")
+var sdJson = `{
+	"difficulty": {
+		"hero": [
+			{"name": "NightMist", "points": -10, "complexity": 3, "archetype": "control", "nemesis": "Gloomweaver" },
+			{"name": "Dark Watch NightMist", "points": 62, "base": "NightMist" },
+			{"name": "Expatriette", "points": 28, "complexity": 1, "archetype": "damage" },
+			{"name": "Dark Watch Expatriette", "points": 42, "base": "Expatriette" },
+			{"name": "Absolute Zero", "points": 25, "complexity": 3, "archetype": "defense" },
+			{"name": "Absolute Zero Elemental Wrath", "points": 31, "base": "Absolute Zero" },
+			{"name": "Bunker", "points": 26, "complexity": 1, "archetype": "damage", "nemesis": "Omnitron" },
+			{"name": "Bunker Engine of War", "points": 20, "base": "Bunker" },
+			{"name": "GI Bunker", "points": -4, "base": "Bunker" },
+			{"name": "Mr. Fixer", "points": 27, "complexity": 2, "archetype": "damage" },
+			{"name": "Dark Watch Fixer", "points": 10, "base": "Mr. Fixer" },
+			{"name": "Setback", "points": 41, "complexity": 2, "archetype": "defense", "nemesis": "Kismet" },
+			{"name": "Dark Watch Setback", "points": 10, "base": "Setback" },
+			{"name": "Haka", "points": -5, "complexity": 1, "archetype": "defense", "nemesis": "Ambuscade" },
+			{"name": "The Eternal Haka", "points": -7, "base": "Haka" },
+			{"name": "Ra", "points": -7, "complexity": 1, "archetype": "damage", "nemesis": "The Ennead" },
+			{"name": "Ra: Horus of Two Horizons", "points": -20, "base": "Ra" },
+			{"name": "Wraith", "points": -6, "complexity": 2, "archetype": "control" },
+			{"name": "Wraith: Price of Freedom", "points": -19, "base": "Wraith" },
+			{"name": "Rook City Wraith", "points": 12, "base": "Wraith" },
+			{"name": "Tempest", "points": -17, "complexity": 2, "archetype": "support", "nemesis": "Grand Warlord Voss" },
+			{"name": "Tempest; Freedom", "points": 1, "base": "Tempest" },
+			{"name": "Fanatic", "points": -1, "complexity": 2, "archetype": "damage", "nemesis": "Apostate" },
+			{"name": "Redeemer Fanatic", "points": -31, "base": "Fanatic" },
+			{"name": "Tachyon", "points": -9, "complexity": 2, "archetype": "damage" },
+			{"name": "Team Leader Tachyon", "points": -71, "base": "Tachyon" },
+			{"name": "Legacy", "points": -45, "complexity": 1, "archetype": "support", "nemesis": "Baron Blade" },
+			{"name": "Young Legacy", "points": -24, "base": "Legacy" },
+			{"name": "The Greatest Legacy", "points": -89, "base": "Legacy" },
+			{"name": "The Visionary", "points": -14, "complexity": 3, "archetype": "control", "nemesis": "The Dreamer" },
+			{"name": "Dark Visionary", "points": -37, "base": "The Visionary" },
+			{"name": "Unity", "points": 5, "complexity": 3, "archetype": "damage" },
+			{"name": "Golem Unity", "points": -14, "base": "Unity" },
+			{"name": "Parse", "points": 30, "complexity": 2, "archetype": "damage" },
+			{"name": "The Sentinels", "points": 30, "complexity": 3, "archetype": "support" },
+			{"name": "The Argent Adept", "points": 11, "complexity": 3, "archetype": "support" },
+			{"name": "The Naturalist", "points": 9, "complexity": 2, "archetype": "defense" },
+			{"name": "Chrono-Ranger", "points": -11, "complexity": 2, "archetype": "damage", "nemesis": "La Capitan" },
+			{"name": "The Scholar", "points": -18, "complexity": 3, "archetype": "support" },
+			{"name": "K.N.Y.F.E.", "points": -32, "complexity": 1, "archetype": "damage" },
+			{"name": "Omnitron-X", "points": -42, "complexity": 3, "archetype": "control", "nemesis": "Omnitron" } ],
+		"villain": [
+			{"name": "Baron Blade", "points": -63, "advanced": 4, "advcount": 170, "tags": ["simple"], "phases": [{"when": "flips", "points": 25}] },
+			{"name": "Mad Bomber Blade", "points": -37, "advanced": 12, "advcount": 61, "base": "Baron Blade" },
+			{"name": "Gloomweaver", "points": -113, "advanced": -71, "advcount": 107, "tags": ["dark"], "phases": [{"when": "flips", "points": 45}] },
+			{"name": "Skinwalker Gloomweaver", "points": 6, "advanced": -4, "advcount": 2, "base": "Gloomweaver", "tags": ["dark"] },
+			{"name": "Spite", "points": -21, "advanced": -25, "advcount": 42, "tags": ["dark"] },
+			{"name": "Agent of Gloom Spite", "points": 5, "advanced": 0, "advcount": 0, "base": "Spite", "tags": ["dark"] },
+			{"name": "Omnitron", "points": 7, "advanced": 39, "advcount": 93, "tags": ["simple"] },
+			{"name": "Cosmic Omnitron", "points": 63, "advanced": 82, "advcount": 51, "base": "Omnitron" },
+			{"name": "The Chairman", "points": 76, "advanced": 46, "advcount": 66, "minutes": 10, "phases": [{"when": "flips", "points": 35}] },
+			{"name": "Iron Legacy", "points": 70, "advanced": 105, "advcount": 62, "minutes": 5 },
+			{"name": "The Matriarch", "points": 57, "advanced": 40, "advcount": 66 },
+			{"name": "The Dreamer", "points": 39, "advanced": 52, "advcount": 55 },
+			{"name": "Vengeful Five", "points": 36, "advanced": 0, "advcount": 0, "minutes": 15 },
+			{"name": "Citizen Dawn", "points": 11, "advanced": 56, "advcount": 85, "minutes": 5, "phases": [{"when": "returns from the sun", "points": 30}] },
+			{"name": "La Capitan", "points": 8, "advanced": 9, "advcount": 66 },
+			{"name": "Grand Warlord Voss", "points": -21, "advanced": 71, "advcount": 115, "tags": ["simple"] },
+			{"name": "Plague Rat", "points": -25, "advanced": 80, "advcount": 86, "tags": ["dark"] },
+			{"name": "Apostate", "points": -37, "advanced": -46, "advcount": 102, "tags": ["dark"] },
+			{"name": "Kismet", "points": -52, "advanced": -31, "advcount": 89 },
+			{"name": "Miss Information", "points": -57, "advanced": 100, "advcount": 64, "minutes": 5, "phases": [{"when": "flips", "points": -30}] },
+			{"name": "Akash'bhuta", "points": -60, "advanced": 20, "advcount": 94, "minutes": 10 },
+			{"name": "The Ennead", "points": -80, "advanced": 66, "advcount": 97, "minutes": 10 },
+			{"name": "Ambuscade", "points": -128, "advanced": -89, "advcount": 87, "tags": ["simple"] }		],
+		"env": [
+			{"name": "Rook City", "points": 74, "pool": "urban" },
+			{"name": "Ruins of Atlantis", "points": 36, "pool": "wild" },
+			{"name": "Insula Primalis", "points": 3, "pool": "wild" },
+			{"name": "Pike Industrial Complex", "points": 3, "pool": "urban" },
+			{"name": "Time Cataclysm", "points": 0, "pool": "temporal", "minutes": 5 },
+			{"name": "Tomb of Anubis", "points": -2, "pool": "mystic" },
+			{"name": "Wagner Mars Base", "points": -3, "tags": ["calm"], "pool": "cosmic" },
+			{"name": "Silver Gulch, 1883", "points": -4, "pool": "temporal" },
+			{"name": "Mobile Defense Platform", "points": -4, "pool": "urban" },
+			{"name": "Realm of Discord", "points": -8, "tags": ["dark"], "pool": "cosmic" },
+			{"name": "Megalopolis", "points": -9, "tags": ["calm"], "pool": "urban" },
+			{"name": "Freedom Tower", "points": -32, "tags": ["calm"], "pool": "urban" },
+			{"name": "The Block", "points": -61, "pool": "temporal" },
+			{"name": "The Final Wasteland", "points": -74, "pool": "temporal", "minutes": 5 }		],
+		"nump": [
+			{"name": "Three", "points": 42 },
+			{"name": "Four", "points": -38 },
+			{"name": "Five", "points": -42 }		]	},
+	"conflicts": [
+		{"villain": "Vengeful Five", "environment": "Time Cataclysm", "reason": "Time Cataclysm's cards that target the villain character are unclear against a team of villains." } ],
+	"scale": [
+		{"total": 500, "losspct": 99 },
+		{"total": 495, "losspct": 99 },
+		{"total": 490, "losspct": 98 },
+		{"total": 484, "losspct": 98 },
+		{"total": 480, "losspct": 98 },
+		{"total": 475, "losspct": 98 },
+		{"total": 470, "losspct": 98 },
+		{"total": 465, "losspct": 98 },
+		{"total": 459, "losspct": 98 },
+		{"total": 455, "losspct": 98 },
+		{"total": 450, "losspct": 97 },
+		{"total": 445, "losspct": 97 },
+		{"total": 440, "losspct": 97 },
+		{"total": 434, "losspct": 97 },
+		{"total": 430, "losspct": 97 },
+		{"total": 425, "losspct": 97 },
+		{"total": 420, "losspct": 96 },
+		{"total": 415, "losspct": 96 },
+		{"total": 409, "losspct": 96 },
+		{"total": 405, "losspct": 96 },
+		{"total": 400, "losspct": 95 },
+		{"total": 395, "losspct": 95 },
+		{"total": 390, "losspct": 95 },
+		{"total": 385, "losspct": 95 },
+		{"total": 380, "losspct": 94 },
+		{"total": 375, "losspct": 94 },
+		{"total": 370, "losspct": 94 },
+		{"total": 365, "losspct": 94 },
+		{"total": 360, "losspct": 93 },
+		{"total": 355, "losspct": 93 },
+		{"total": 350, "losspct": 92 },
+		{"total": 345, "losspct": 92 },
+		{"total": 340, "losspct": 92 },
+		{"total": 335, "losspct": 91 },
+		{"total": 330, "losspct": 91 },
+		{"total": 325, "losspct": 90 },
+		{"total": 320, "losspct": 90 },
+		{"total": 315, "losspct": 90 },
+		{"total": 310, "losspct": 89 },
+		{"total": 305, "losspct": 88 },
+		{"total": 300, "losspct": 88 },
+		{"total": 295, "losspct": 87 },
+		{"total": 290, "losspct": 87 },
+		{"total": 285, "losspct": 86 },
+		{"total": 280, "losspct": 86 },
+		{"total": 275, "losspct": 85 },
+		{"total": 270, "losspct": 84 },
+		{"total": 265, "losspct": 84 },
+		{"total": 260, "losspct": 83 },
+		{"total": 254, "losspct": 82 },
+		{"total": 250, "losspct": 81 },
+		{"total": 245, "losspct": 81 },
+		{"total": 240, "losspct": 80 },
+		{"total": 235, "losspct": 79 },
+		{"total": 229, "losspct": 78 },
+		{"total": 225, "losspct": 77 },
+		{"total": 220, "losspct": 76 },
+		{"total": 215, "losspct": 75 },
+		{"total": 210, "losspct": 74 },
+		{"total": 204, "losspct": 73 },
+		{"total": 200, "losspct": 72 },
+		{"total": 195, "losspct": 71 },
+		{"total": 190, "losspct": 70 },
+		{"total": 185, "losspct": 69 },
+		{"total": 180, "losspct": 68 },
+		{"total": 175, "losspct": 67 },
+		{"total": 170, "losspct": 66 },
+		{"total": 165, "losspct": 65 },
+		{"total": 160, "losspct": 64 },
+		{"total": 155, "losspct": 63 },
+		{"total": 150, "losspct": 61 },
+		{"total": 145, "losspct": 60 },
+		{"total": 140, "losspct": 59 },
+		{"total": 135, "losspct": 58 },
+		{"total": 130, "losspct": 57 },
+		{"total": 125, "losspct": 55 },
+		{"total": 120, "losspct": 54 },
+		{"total": 114, "losspct": 53 },
+		{"total": 110, "losspct": 52 },
+		{"total": 105, "losspct": 50 },
+		{"total": 100, "losspct": 49 },
+		{"total": 95, "losspct": 48 },
+		{"total": 90, "losspct": 47 },
+		{"total": 85, "losspct": 45 },
+		{"total": 80, "losspct": 44 },
+		{"total": 75, "losspct": 43 },
+		{"total": 70, "losspct": 42 },
+		{"total": 65, "losspct": 40 },
+		{"total": 60, "losspct": 39 },
+		{"total": 55, "losspct": 38 },
+		{"total": 50, "losspct": 37 },
+		{"total": 45, "losspct": 36 },
+		{"total": 40, "losspct": 35 },
+		{"total": 35, "losspct": 34 },
+		{"total": 30, "losspct": 32 },
+		{"total": 25, "losspct": 31 },
+		{"total": 20, "losspct": 30 },
+		{"total": 15, "losspct": 29 },
+		{"total": 10, "losspct": 28 },
+		{"total": 5, "losspct": 27 },
+		{"total": 0, "losspct": 26 },
+		{"total": -5, "losspct": 25 },
+		{"total": -10, "losspct": 24 },
+		{"total": -15, "losspct": 24 },
+		{"total": -20, "losspct": 23 },
+		{"total": -25, "losspct": 22 },
+		{"total": -30, "losspct": 21 },
+		{"total": -35, "losspct": 20 },
+		{"total": -40, "losspct": 19 },
+		{"total": -45, "losspct": 19 },
+		{"total": -50, "losspct": 18 },
+		{"total": -55, "losspct": 17 },
+		{"total": -60, "losspct": 17 },
+		{"total": -65, "losspct": 16 },
+		{"total": -70, "losspct": 15 },
+		{"total": -75, "losspct": 15 },
+		{"total": -80, "losspct": 14 },
+		{"total": -85, "losspct": 13 },
+		{"total": -90, "losspct": 13 },
+		{"total": -95, "losspct": 12 },
+		{"total": -100, "losspct": 12 },
+		{"total": -105, "losspct": 11 },
+		{"total": -110, "losspct": 11 },
+		{"total": -114, "losspct": 10 },
+		{"total": -120, "losspct": 10 },
+		{"total": -125, "losspct": 10 },
+		{"total": -130, "losspct": 9 },
+		{"total": -135, "losspct": 9 },
+		{"total": -140, "losspct": 8 },
+		{"total": -145, "losspct": 8 },
+		{"total": -150, "losspct": 8 },
+		{"total": -155, "losspct": 7 },
+		{"total": -160, "losspct": 7 },
+		{"total": -165, "losspct": 7 },
+		{"total": -170, "losspct": 6 },
+		{"total": -175, "losspct": 6 },
+		{"total": -180, "losspct": 6 },
+		{"total": -185, "losspct": 6 },
+		{"total": -190, "losspct": 5 },
+		{"total": -195, "losspct": 5 },
+		{"total": -200, "losspct": 5 },
+		{"total": -204, "losspct": 5 },
+		{"total": -210, "losspct": 5 },
+		{"total": -215, "losspct": 4 },
+		{"total": -220, "losspct": 4 },
+		{"total": -225, "losspct": 4 },
+		{"total": -229, "losspct": 4 },
+		{"total": -235, "losspct": 4 },
+		{"total": -240, "losspct": 4 },
+		{"total": -245, "losspct": 3 },
+		{"total": -250, "losspct": 3 },
+		{"total": -254, "losspct": 3 },
+		{"total": -260, "losspct": 3 },
+		{"total": -265, "losspct": 3 },
+		{"total": -270, "losspct": 3 },
+		{"total": -275, "losspct": 3 },
+		{"total": -280, "losspct": 3 },
+		{"total": -285, "losspct": 2 },
+		{"total": -290, "losspct": 2 },
+		{"total": -295, "losspct": 2 },
+		{"total": -300, "losspct": 2 },
+		{"total": -305, "losspct": 2 },
+		{"total": -310, "losspct": 2 },
+		{"total": -315, "losspct": 2 },
+		{"total": -320, "losspct": 2 },
+		{"total": -325, "losspct": 2 },
+		{"total": -330, "losspct": 2 },
+		{"total": -335, "losspct": 2 },
+		{"total": -340, "losspct": 2 },
+		{"total": -345, "losspct": 2 },
+		{"total": -350, "losspct": 2 },
+		{"total": -355, "losspct": 1 },
+		{"total": -360, "losspct": 1 },
+		{"total": -365, "losspct": 1 },
+		{"total": -370, "losspct": 1 },
+		{"total": -375, "losspct": 1 },
+		{"total": -380, "losspct": 1 },
+		{"total": -385, "losspct": 1 },
+		{"total": -390, "losspct": 1 },
+		{"total": -395, "losspct": 1 },
+		{"total": -400, "losspct": 1 },
+		{"total": -405, "losspct": 1 },
+		{"total": -409, "losspct": 1 },
+		{"total": -415, "losspct": 1 },
+		{"total": -420, "losspct": 1 },
+		{"total": -425, "losspct": 1 },
+		{"total": -430, "losspct": 1 },
+		{"total": -434, "losspct": 1 },
+		{"total": -440, "losspct": 1 },
+		{"total": -445, "losspct": 1 },
+		{"total": -450, "losspct": 1 },
+		{"total": -455, "losspct": 1 },
+		{"total": -459, "losspct": 1 },
+		{"total": -465, "losspct": 1 },
+		{"total": -470, "losspct": 1 },
+		{"total": -475, "losspct": 1 },
+		{"total": -480, "losspct": 1 },
+		{"total": -484, "losspct": 1 },
+		{"total": -490, "losspct": 1 },
+		{"total": -495, "losspct": 1 }	]
+}`
+
+var ExpansionCards = map[ExpansionType][]string{
+	MiniExpansion: {
+		"The Scholar",
+		"Unity",
+		"Ambuscade",
+		"Miss Information",
+		"The Final Wasteland",
+		"Silver Gulch, 1883",
+	},
+	RookCity: {
+		"Expatriette",
+		"Mr. Fixer",
+		"Pike Industrial Complex",
+		"Rook City",
+		"The Chairman",
+		"The Matriarch",
+		"Plague Rat",
+		"Spite",
+	},
+	InfernalRelics: {
+		"The Argent Adept",
+		"NightMist",
+		"Akash'bhuta",
+		"Apostate",
+		"Gloomweaver",
+		"The Ennead",
+		"Realm of Discord",
+		"Tomb of Anubis",
+	},
+	ShatteredTimelines: {
+		"Omnitron-X",
+		"Chrono-Ranger",
+		"Iron Legacy",
+		"The Dreamer",
+		"La Capitan",
+		"Kismet",
+		"Time Cataclysm",
+		"The Block",
+	},
+	Vengeance: {
+		"K.N.Y.F.E.",
+		"The Sentinels",
+		"The Naturalist",
+		"Setback",
+		"Parse",
+		"Vengeful Five", // treated as one villain; here they are:
+		//"Fright Train",
+		//"Ermine",
+		//"Proletariat",
+		//"Friction",
+		//"Baron Blade Vengeance",
+		"Mobile Defense Platform",
+		"Freedom Tower",
+	},
+	Promos: {
+		"Dark Watch NightMist",
+		"Dark Watch Expatriette",
+		"Absolute Zero Elemental Wrath",
+		"Bunker Engine of War",
+		"GI Bunker",
+		"Dark Watch Fixer",
+		"Dark Watch Setback",
+		"The Eternal Haka",
+		"Ra: Horus of Two Horizons",
+		"Wraith: Price of Freedom",
+		"Rook City Wraith",
+		"Tempest; Freedom",
+		"Redeemer Fanatic",
+		"Team Leader Tachyon",
+		"Young Legacy",
+		"The Greatest Legacy",
+		"Dark Visionary",
+		"Golem Unity",
+		"Mad Bomber Blade",
+		"Skinwalker Gloomweaver",
+		"Agent of Gloom Spite",
+		"Cosmic Omnitron",
+	},
+}
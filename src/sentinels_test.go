@@ -0,0 +1,301 @@
+package sentinels
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	randv2 "math/rand/v2"
+	"sort"
+	"testing"
+)
+
+func TestDifficultyRange(t *testing.T) {
+	prev := 0
+	for lp := 1; lp < 100; lp++ {
+		min, max := DifficultyRange(lp)
+		if min > max {
+			t.Errorf("DifficultyRange(%d) = %d, %d; min is above max", lp, min, max)
+		}
+		if lp > 1 && min <= prev {
+			t.Errorf("DifficultyRange(%d) = %d, %d; overlaps %d%%, which ends at %d", lp, min, max, lp-1, prev)
+		}
+		prev = max
+		for _, total := range []int{min, max} {
+			if got := LossPercent(total); got != lp {
+				t.Errorf("LossPercent(%d) = %d, want %d", total, got, lp)
+			}
+		}
+	}
+}
+
+// findSetup runs a search for p from seed without recording the setup in
+// the history.
+func findSetup(p *Params, seed int64) (*Setup, error) {
+	q, err := newSearchFor(p)
+	if err != nil {
+		return nil, err
+	}
+	s, _, err := q.run(seed)
+	return s, err
+}
+
+// checkProperties reports a setup found for p that breaks one of the
+// properties every setup should have, using Check as the oracle for the
+// rest.
+func checkProperties(t *testing.T, p *Params, s *Setup) {
+	t.Helper()
+	bases := make(map[string]bool)
+	for _, h := range s.Heroes {
+		if bases[h.Base] && len(p.Copies) == 0 {
+			t.Errorf("%+v: two heroes share the %s deck: %s", *p, h.Base, s)
+		}
+		bases[h.Base] = true
+	}
+	if !s.Approximate && !p.ByTotal {
+		if _, min, max := p.lossRange(loaded().sd); s.Difficulty < min || s.Difficulty > max {
+			t.Errorf("%+v: difficulty %d is outside %d to %d: %s", *p, s.Difficulty, min, max, s)
+		}
+	}
+	for _, c := range append([]*Card{s.Villain, s.Environment}, s.Heroes...) {
+		if !hasExpansion(p.Expansions, c.Expansion) && !namesCard(p.Heroes, c.Name) {
+			t.Errorf("%+v: %s is from %s, which wasn't selected: %s", *p, c.Name, c.Expansion, s)
+		}
+	}
+	if err := s.Check(p); err != nil {
+		t.Errorf("%+v: %v: %s", *p, err, s)
+	}
+}
+
+func TestFindProperties(t *testing.T) {
+	exps := [][]ExpansionType{
+		{BaseSet},
+		{BaseSet, MiniExpansion},
+		{BaseSet, RookCity, InfernalRelics},
+		allExpansions(),
+	}
+	for _, players := range []int{3, 4, 5} {
+		for _, lp := range []int{10, 50, 90} {
+			for i, exp := range exps {
+				p := &Params{Players: players, LossPercent: lp, Range: 10, Expansions: exp, MaxIterations: 5000}
+				s, err := findSetup(p, int64(100*players+lp+i))
+				if err != nil {
+					t.Errorf("%+v: %v", *p, err)
+					continue
+				}
+				checkProperties(t, p, s)
+			}
+		}
+	}
+}
+
+func FuzzFind(f *testing.F) {
+	f.Add(3, 50, 10, uint8(1), false, int64(1))
+	f.Add(5, 95, 0, uint8(0x7f), true, int64(2))
+	f.Add(4, 5, 3, uint8(0x0c), false, int64(3))
+	f.Add(2, 50, 10, uint8(0), false, int64(4))
+	f.Fuzz(func(t *testing.T, players, lp, rg int, exp uint8, advanced bool, seed int64) {
+		p := &Params{Players: players, LossPercent: lp, Range: rg, Advanced: advanced, MaxIterations: 1000}
+		for e := range ExpansionNames {
+			if exp&(1<<e) != 0 {
+				p.Expansions = append(p.Expansions, ExpansionType(e))
+			}
+		}
+		s, err := checkOne(p, seed)
+		if f, ok := err.(*CheckFailure); ok {
+			t.Fatal(f)
+		}
+		if s != nil {
+			checkProperties(t, p, s)
+		}
+	})
+}
+
+func TestCheckGolden(t *testing.T) {
+	for _, d := range CheckGolden(nil) {
+		if d.Severity == "error" {
+			t.Errorf("built-in data: %s", d)
+		}
+	}
+}
+
+func TestCopies(t *testing.T) {
+	repeated := 0
+	for seed := int64(1); seed <= 200; seed++ {
+		p := &Params{Players: 5, LossPercent: 50, Range: 1000, Expansions: []ExpansionType{BaseSet}, Copies: map[ExpansionType]int{BaseSet: 2}}
+		s, err := findSetup(p, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkProperties(t, p, s)
+		names := make(map[string]bool)
+		for _, h := range s.Heroes {
+			if names[h.Name] {
+				repeated++
+			}
+			names[h.Name] = true
+		}
+	}
+	if repeated == 0 {
+		t.Error("no hero was picked twice with two copies of each deck")
+	}
+}
+
+func TestPick(t *testing.T) {
+	for _, tc := range []struct {
+		n, m    int
+		wantErr bool
+	}{
+		{5, 5, false},
+		{1, 1, false},
+		{10, 1, false},
+		{10, 0, false},
+		{0, 0, false},
+		{100000, 7, false},
+		{100000, 100000, false},
+		{3, 4, true},
+		{0, 1, true},
+		{-1, 0, true},
+		{5, -1, true},
+	} {
+		rng := randv2.New(randv2.NewPCG(uint64(tc.n), uint64(tc.m)))
+		calls := 0
+		intn := func(k int) int {
+			calls++
+			if k <= 0 {
+				t.Fatalf("pick(%d, %d) called intn(%d)", tc.n, tc.m, k)
+			}
+			return rng.IntN(k)
+		}
+		p, err := pick(intn, tc.n, tc.m)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("pick(%d, %d) = %v, want an error", tc.n, tc.m, p)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("pick(%d, %d): %v", tc.n, tc.m, err)
+			continue
+		}
+		if len(p) != tc.m || calls != tc.m {
+			t.Errorf("pick(%d, %d) picked %d numbers with %d calls", tc.n, tc.m, len(p), calls)
+		}
+		seen := make(map[int]bool)
+		for _, x := range p {
+			if x < 0 || x >= tc.n || seen[x] {
+				t.Errorf("pick(%d, %d) picked %d twice or out of range", tc.n, tc.m, x)
+				break
+			}
+			seen[x] = true
+		}
+	}
+}
+
+// binomial returns the number of ways to choose m things from n.
+func binomial(n, m int) float64 {
+	b := 1.0
+	for i := 0; i < m; i++ {
+		b = b * float64(n-i) / float64(i+1)
+	}
+	return b
+}
+
+// TestPickUniform checks that pick chooses every set of m numbers from n
+// equally often, with a chi-squared test at the 0.1% level.  The sizes are
+// like choosing heroes from a small card set, one hero, and all of them.
+func TestPickUniform(t *testing.T) {
+	const trials = 100000
+	for _, c := range [][2]int{{10, 3}, {10, 5}, {5, 1}, {6, 5}} {
+		n, m := c[0], c[1]
+		sets := binomial(n, m)
+		rng := randv2.New(randv2.NewPCG(1, pcgStream))
+		counts := make(map[string]int)
+		for i := 0; i < trials; i++ {
+			p, err := pick(rng.IntN, n, m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Ints(p)
+			counts[fmt.Sprint(p)]++
+		}
+		want := float64(trials) / sets
+		chi2 := (sets - float64(len(counts))) * want // sets never picked
+		for _, c := range counts {
+			d := float64(c) - want
+			chi2 += d * d / want
+		}
+		// the Wilson-Hilferty approximation of the 99.9th percentile of the
+		// chi-squared distribution.
+		df := sets - 1
+		k := 2 / (9 * df)
+		crit := df * math.Pow(1-k+3.09*math.Sqrt(k), 3)
+		if chi2 > crit {
+			t.Errorf("picking %d of %d isn't uniform: chi-squared is %.1f, above %.1f", m, n, chi2, crit)
+		}
+	}
+}
+
+func TestOverlayMatchesCardKey(t *testing.T) {
+	sd, err := buildData(sdBytes, []byte(`{"hero": [{"name": "knyfe", "points": -10, "complexity": 1}], "hide": ["knyfe"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, d := range sd.Difficulty.Hero {
+		if CardKey(d.Name) != "knyfe" {
+			continue
+		}
+		n++
+		if d.Name != "K.N.Y.F.E." || d.Points != -10 || !d.Hidden {
+			t.Errorf("overlaid card is %+v", d)
+		}
+	}
+	if n != 1 {
+		t.Errorf("%d cards are keyed knyfe, want 1", n)
+	}
+}
+
+func TestSearchByCardKey(t *testing.T) {
+	h := &History{Entries: []*HistoryEntry{
+		{Token: "a", Heroes: []string{"K.N.Y.F.E.", "Haka", "Legacy"}, Villain: "Baron Blade", Environment: "Insula Primalis"},
+		{Token: "b", Heroes: []string{"Tachyon", "Haka", "Legacy"}, Villain: "Omnitron", Environment: "Megalopolis"},
+	}}
+	for _, q := range []*HistoryQuery{
+		{Hero: "knyfe"},
+		{Villain: "Baron blade"},
+		{Villain: "baronblade", Environment: "insula-primalis"},
+	} {
+		if r, n := h.Search(q); n != 1 || r[0].Token != "a" {
+			t.Errorf("%+v: found %d entries", *q, n)
+		}
+	}
+}
+
+func TestArchiveCustomCards(t *testing.T) {
+	custom := []byte(`{"hero": [{"name": "knyfe", "points": -10, "complexity": 1}]}`)
+	h := &History{Entries: []*HistoryEntry{{Token: "a", Heroes: []string{"Legacy"}, Villain: "Baron Blade", Environment: "Megalopolis"}}}
+	var buf bytes.Buffer
+	if err := Export(&buf, &Profiles{}, h, custom); err != nil {
+		t.Fatal(err)
+	}
+	archive := bytes.NewReader(buf.Bytes())
+
+	ps, h2 := &Profiles{}, &History{}
+	if err := Import(archive, archive.Size(), ps, h2, nil); err == nil || len(h2.Entries) != 0 {
+		t.Errorf("imported custom cards with nowhere to restore them: %v", err)
+	}
+	if err := Import(archive, archive.Size(), ps, h2, func([]byte) error { return errors.New("Bad cards.") }); err == nil || len(h2.Entries) != 0 {
+		t.Errorf("imported the archive though its custom cards were rejected: %v", err)
+	}
+	var restored []byte
+	if err := Import(archive, archive.Size(), ps, h2, func(b []byte) error { restored = b; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(restored, custom) || len(h2.Entries) != 1 {
+		t.Errorf("restored %q and %d history entries", restored, len(h2.Entries))
+	}
+	if err := CheckLayers(nil, restored); err != nil {
+		t.Error(err)
+	}
+}
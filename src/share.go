@@ -0,0 +1,34 @@
+package sentinels
+
+import (
+	"errors"
+	"net/url"
+)
+
+// ShareQuery returns the setup's cards as a URL query, such as
+// "env=Megalopolis&hero=Legacy&hero=Bunker&hero=Tachyon&villain=Baron+Blade",
+// that ParseShareQuery turns back into the setup.
+func (s *Setup) ShareQuery() string {
+	v := url.Values{}
+	for _, h := range s.Heroes {
+		v.Add("hero", h.Name)
+	}
+	v.Set("villain", s.Villain.Name)
+	v.Set("env", s.Environment.Name)
+	if s.Advanced {
+		v.Set("adv", "1")
+	}
+	return v.Encode()
+}
+
+// ParseShareQuery scores the setup described by a query from ShareQuery.
+func ParseShareQuery(query string) (*Setup, error) {
+	v, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if v.Get("villain") == "" || v.Get("env") == "" {
+		return nil, errors.New("The shared setup has no villain or environment.")
+	}
+	return Score(v["hero"], v.Get("villain"), v.Get("env"), v.Get("adv") != "")
+}
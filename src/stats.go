@@ -0,0 +1,82 @@
+package sentinels
+
+import (
+	"sort"
+)
+
+// CardStats summarizes how one card has fared in the history.
+type CardStats struct {
+	Name     string
+	Picks    int     // setups the card was in
+	PickRate float64 // fraction of all setups the card was in
+	Games    int     // of those, setups with a recorded result
+	Wins     int
+	WinRate  float64 // fraction of Games the heroes won
+}
+
+// Stats summarizes the setups in a history.  Vetoed setups are left out.
+type Stats struct {
+	Setups int
+	Cards  []*CardStats // most picked first
+	// Together counts the setups each pair of cards was in together.
+	Together map[string]map[string]int
+}
+
+// Stats aggregates the history into per-card pick and win rates and a
+// co-occurrence matrix.
+func (h *History) Stats() *Stats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st := &Stats{Together: make(map[string]map[string]int)}
+	byName := make(map[string]*CardStats)
+	for _, e := range h.Entries {
+		if e.Vetoed {
+			continue
+		}
+		st.Setups++
+		names := append(append([]string(nil), e.Heroes...), e.Villain, e.Environment)
+		for _, n := range names {
+			cs := byName[n]
+			if cs == nil {
+				cs = &CardStats{Name: n}
+				byName[n] = cs
+				st.Cards = append(st.Cards, cs)
+				st.Together[n] = make(map[string]int)
+			}
+			cs.Picks++
+			if e.Result != "" {
+				cs.Games++
+				if e.Result == Won {
+					cs.Wins++
+				}
+			}
+		}
+		for _, a := range names {
+			for _, b := range names {
+				if a != b {
+					st.Together[a][b]++
+				}
+			}
+		}
+	}
+	for _, cs := range st.Cards {
+		cs.PickRate = float64(cs.Picks) / float64(st.Setups)
+		if cs.Games > 0 {
+			cs.WinRate = float64(cs.Wins) / float64(cs.Games)
+		}
+	}
+	sort.Sort(byPicks(st.Cards))
+	return st
+}
+
+// byPicks sorts card stats by descending picks, then by name.
+type byPicks []*CardStats
+
+func (b byPicks) Len() int      { return len(b) }
+func (b byPicks) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byPicks) Less(i, j int) bool {
+	if b[i].Picks != b[j].Picks {
+		return b[i].Picks > b[j].Picks
+	}
+	return b[i].Name < b[j].Name
+}
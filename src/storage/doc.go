@@ -0,0 +1,4 @@
+// Package storage provides stores for the sentinels engine's profiles and
+// history besides its own FileStore: MemoryStore for tests and deployments
+// without a disk, and SQLStore for a SQL database.
+package storage
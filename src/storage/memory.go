@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// MemoryStore keeps profiles and history in memory, e.g. for tests or
+// deployments without a disk.  It stores copies, so later changes to what
+// was put aren't seen until it is put again.
+type MemoryStore struct {
+	mu       sync.Mutex
+	profiles []byte
+	history  [][]byte // one encoded entry each
+}
+
+func (ms *MemoryStore) Profiles() (*sentinels.Profiles, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ps := &sentinels.Profiles{}
+	if ms.profiles == nil {
+		return ps, nil
+	}
+	return ps, json.Unmarshal(ms.profiles, ps)
+}
+
+func (ms *MemoryStore) PutProfiles(ps *sentinels.Profiles) error {
+	b, err := json.Marshal(ps)
+	if err != nil {
+		return err
+	}
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.profiles = b
+	return nil
+}
+
+func (ms *MemoryStore) History() ([]*sentinels.HistoryEntry, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	var r []*sentinels.HistoryEntry
+	for _, b := range ms.history {
+		e := &sentinels.HistoryEntry{}
+		if err := json.Unmarshal(b, e); err != nil {
+			return nil, err
+		}
+		r = append(r, e)
+	}
+	return r, nil
+}
+
+func (ms *MemoryStore) PutHistory(entries []*sentinels.HistoryEntry) error {
+	h := make([][]byte, len(entries))
+	for i, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		h[i] = b
+	}
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.history = h
+	return nil
+}
+
+func (ms *MemoryStore) AddHistory(e *sentinels.HistoryEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.history = append(ms.history, b)
+	return nil
+}
@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/uhhhclem/sentinels"
+)
+
+// SQLStore keeps profiles and history in a SQL database such as SQLite.  The
+// caller opens the database, so the choice of driver is theirs; statements
+// use ? placeholders.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// sqlSchema creates the tables SQLStore uses.
+var sqlSchema = []string{
+	`CREATE TABLE IF NOT EXISTS sentinels_profiles (kind TEXT NOT NULL, name TEXT NOT NULL, data TEXT NOT NULL, PRIMARY KEY (kind, name))`,
+	`CREATE TABLE IF NOT EXISTS sentinels_history (seq INTEGER NOT NULL PRIMARY KEY, token TEXT NOT NULL, data TEXT NOT NULL)`,
+}
+
+// Kinds of rows in the profiles table.
+const (
+	sqlProfile = "profile"
+	sqlPreset  = "preset"
+	sqlPlayer  = "player"
+)
+
+// NewSQLStore returns a store using db, creating its tables if need be.
+func NewSQLStore(db *sql.DB) (*SQLStore, error) {
+	for _, s := range sqlSchema {
+		if _, err := db.Exec(s); err != nil {
+			return nil, err
+		}
+	}
+	return &SQLStore{db}, nil
+}
+
+func (ss *SQLStore) Profiles() (*sentinels.Profiles, error) {
+	rows, err := ss.db.Query(`SELECT kind, name, data FROM sentinels_profiles`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ps := &sentinels.Profiles{ByName: make(map[string]*sentinels.Profile), Presets: make(map[string]*sentinels.Params), Players: make(map[string]*sentinels.Player)}
+	for rows.Next() {
+		var kind, name, data string
+		if err := rows.Scan(&kind, &name, &data); err != nil {
+			return nil, err
+		}
+		switch kind {
+		case sqlProfile:
+			p := &sentinels.Profile{}
+			if err := json.Unmarshal([]byte(data), p); err != nil {
+				return nil, err
+			}
+			ps.ByName[name] = p
+		case sqlPreset:
+			p := &sentinels.Params{}
+			if err := json.Unmarshal([]byte(data), p); err != nil {
+				return nil, err
+			}
+			ps.Presets[name] = p
+		case sqlPlayer:
+			p := &sentinels.Player{}
+			if err := json.Unmarshal([]byte(data), p); err != nil {
+				return nil, err
+			}
+			ps.Players[name] = p
+		}
+	}
+	return ps, rows.Err()
+}
+
+func (ss *SQLStore) PutProfiles(ps *sentinels.Profiles) error {
+	tx, err := ss.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM sentinels_profiles`); err != nil {
+		return err
+	}
+	put := func(kind, name string, v interface{}) error {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO sentinels_profiles (kind, name, data) VALUES (?, ?, ?)`, kind, name, string(b))
+		return err
+	}
+	for n, p := range ps.ByName {
+		if err := put(sqlProfile, n, p); err != nil {
+			return err
+		}
+	}
+	for n, p := range ps.Presets {
+		if err := put(sqlPreset, n, p); err != nil {
+			return err
+		}
+	}
+	for n, p := range ps.Players {
+		if err := put(sqlPlayer, n, p); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (ss *SQLStore) History() ([]*sentinels.HistoryEntry, error) {
+	rows, err := ss.db.Query(`SELECT data FROM sentinels_history ORDER BY seq`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var r []*sentinels.HistoryEntry
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		e := &sentinels.HistoryEntry{}
+		if err := json.Unmarshal([]byte(data), e); err != nil {
+			return nil, err
+		}
+		r = append(r, e)
+	}
+	return r, rows.Err()
+}
+
+func (ss *SQLStore) PutHistory(entries []*sentinels.HistoryEntry) error {
+	tx, err := ss.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM sentinels_history`); err != nil {
+		return err
+	}
+	for i, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO sentinels_history (seq, token, data) VALUES (?, ?, ?)`, i, e.Token, string(b)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (ss *SQLStore) AddHistory(e *sentinels.HistoryEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = ss.db.Exec(`INSERT INTO sentinels_history (seq, token, data) SELECT COALESCE(MAX(seq), -1) + 1, ?, ? FROM sentinels_history`, e.Token, string(b))
+	return err
+}
@@ -0,0 +1,159 @@
+package sentinels
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// Store persists profiles, presets and history, including recorded results.
+// AddHistory appends one entry to the stored history, so that recording a
+// setup doesn't rewrite all the others.
+type Store interface {
+	Profiles() (*Profiles, error)
+	PutProfiles(ps *Profiles) error
+	History() ([]*HistoryEntry, error)
+	PutHistory(entries []*HistoryEntry) error
+	AddHistory(e *HistoryEntry) error
+}
+
+// OpenProfiles loads profiles from st and saves later changes to it,
+// including the villains drawn from the groups' rotations.
+func OpenProfiles(st Store) (*Profiles, error) {
+	ps, err := st.Profiles()
+	if err != nil {
+		return nil, err
+	}
+	ps.Store = st
+	Listen(ps.rotate)
+	return ps, nil
+}
+
+// OpenHistory loads a history from st and saves later changes to it.
+func OpenHistory(st Store) (*History, error) {
+	e, err := st.History()
+	if err != nil {
+		return nil, err
+	}
+	return &History{Store: st, Entries: e}, nil
+}
+
+// FileStore keeps profiles and history in JSON files.  A missing file reads
+// as empty, and an empty path isn't read or written.
+type FileStore struct {
+	ProfilesPath string
+	HistoryPath  string
+}
+
+func (fs *FileStore) Profiles() (*Profiles, error) {
+	ps := &Profiles{}
+	return ps, readJSON(fs.ProfilesPath, ps)
+}
+
+func (fs *FileStore) PutProfiles(ps *Profiles) error {
+	return writeJSON(fs.ProfilesPath, ps)
+}
+
+func (fs *FileStore) History() ([]*HistoryEntry, error) {
+	var e []*HistoryEntry
+	return e, readJSON(fs.HistoryPath, &e)
+}
+
+func (fs *FileStore) PutHistory(entries []*HistoryEntry) error {
+	return writeJSON(fs.HistoryPath, entries)
+}
+
+// AddHistory writes e in place of the end of the history file's array, and
+// rewrites the file only if it doesn't end in one.
+func (fs *FileStore) AddHistory(e *HistoryEntry) error {
+	if fs.HistoryPath == "" {
+		return nil
+	}
+	f, err := os.OpenFile(fs.HistoryPath, os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		return writeJSON(fs.HistoryPath, []*HistoryEntry{e})
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	off, empty, ok := arrayEnd(f)
+	if !ok {
+		entries, err := fs.History()
+		if err != nil {
+			return err
+		}
+		return fs.PutHistory(append(entries, e))
+	}
+	b, err := json.MarshalIndent(e, "\t", "\t")
+	if err != nil {
+		return err
+	}
+	sep := ",\n\t"
+	if empty {
+		sep = "\n\t"
+	}
+	b = append([]byte(sep), append(b, "\n]"...)...)
+	if _, err := f.WriteAt(b, off); err != nil {
+		return err
+	}
+	if err := f.Truncate(off + int64(len(b))); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// arrayEnd returns the offset just past the last element of the JSON array
+// a file holds, or past its opening bracket if the array is empty.  It
+// looks only at the end of the file, and ok is false if that isn't the end
+// of an array.
+func arrayEnd(f *os.File) (off int64, empty, ok bool) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, false, false
+	}
+	n := fi.Size()
+	if n > 512 {
+		n = 512
+	}
+	tail := make([]byte, n)
+	if _, err := f.ReadAt(tail, fi.Size()-n); err != nil && err != io.EOF {
+		return 0, false, false
+	}
+	t := bytes.TrimRight(tail, " \t\r\n")
+	if len(t) == 0 || t[len(t)-1] != ']' {
+		return 0, false, false
+	}
+	before := bytes.TrimRight(t[:len(t)-1], " \t\r\n")
+	if len(before) == 0 {
+		return 0, false, false
+	}
+	return fi.Size() - n + int64(len(before)), before[len(before)-1] == '[', true
+}
+
+func readJSON(path string, v interface{}) error {
+	if path == "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func writeJSON(path string, v interface{}) error {
+	if path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
@@ -0,0 +1,73 @@
+package sentinels
+
+import "fmt"
+
+// Style says how a setup's difficulty is to be reached, since the same total
+// plays very differently when it comes from the heroes than when it comes
+// from the villain and environment.
+type Style int
+
+const (
+	AnyStyle Style = iota
+	// PowerFantasy sends a strong team, whose heroes' points are negative,
+	// against a villain and environment whose points are positive.
+	PowerFantasy
+	// Underdog sends a weak team, whose heroes' points are positive,
+	// against a villain and environment whose points are negative.
+	Underdog
+)
+
+// StyleNames are the names of the styles, indexed by Style.
+var StyleNames = []string{"any", "powerfantasy", "underdog"}
+
+func (st Style) String() string {
+	if st < 0 || int(st) >= len(StyleNames) {
+		return fmt.Sprintf("Style(%d)", int(st))
+	}
+	return StyleNames[st]
+}
+
+// MarshalText encodes a style by name, so presets stay readable.
+func (st Style) MarshalText() ([]byte, error) {
+	if st < 0 || int(st) >= len(StyleNames) {
+		return nil, fmt.Errorf("Unknown style %d.", int(st))
+	}
+	return []byte(StyleNames[st]), nil
+}
+
+func (st *Style) UnmarshalText(b []byte) error {
+	v, err := ParseStyle(string(b))
+	if err != nil {
+		return err
+	}
+	*st = v
+	return nil
+}
+
+// ParseStyle looks up a style by name, ignoring case, spaces and
+// punctuation, so that "power fantasy" is PowerFantasy.  An empty name is
+// AnyStyle.
+func ParseStyle(name string) (Style, error) {
+	if name == "" {
+		return AnyStyle, nil
+	}
+	for i, n := range StyleNames {
+		if n == expansionKey(name) {
+			return Style(i), nil
+		}
+	}
+	return AnyStyle, fmt.Errorf("Unknown style %q.", name)
+}
+
+// Fits reports whether the setup's points are split the way the style
+// asks for.
+func (st Style) Fits(s *Setup) bool {
+	b := s.Breakdown()
+	switch st {
+	case PowerFantasy:
+		return b.Heroes < 0 && b.Villain+b.Environment > 0
+	case Underdog:
+		return b.Heroes > 0 && b.Villain+b.Environment < 0
+	}
+	return true
+}
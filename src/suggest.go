@@ -0,0 +1,86 @@
+package sentinels
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Swap is a single-card change to a setup.
+type Swap struct {
+	Out    *Card
+	In     *Card
+	Change int // change in difficulty
+}
+
+// String describes the swap, e.g. "swap Megalopolis for Rook City to add
+// ~83 points".
+func (w Swap) String() string {
+	verb, n := "add", w.Change
+	if n < 0 {
+		verb, n = "remove", -n
+	}
+	return fmt.Sprintf("swap %s for %s to %s ~%d points", w.Out.Name, w.In.Name, verb, n)
+}
+
+// Suggest returns up to n single-card swaps that change the setup's
+// difficulty by about delta points, closest first.  Replacements come from
+// the card set the setup was generated from, and never duplicate a base
+// hero already in the setup.
+func (s *Setup) Suggest(delta, n int) []Swap {
+	cs := Select(WithExpansions(allExpansions()...))
+	if s.search != nil {
+		cs = s.search.cs
+	}
+	var swaps []Swap
+	try := func(out, in *Card, replace func(*Setup, *Card)) {
+		t := *s
+		t.Heroes = append([]*Card(nil), s.Heroes...)
+		replace(&t, in)
+		swaps = append(swaps, Swap{Out: out, In: in, Change: Model.Score(&t) - s.Difficulty})
+	}
+	for i := range s.Heroes {
+		h := s.Heroes[i]
+		bases := make(map[string]bool)
+		for j, o := range s.Heroes {
+			if j != i {
+				bases[o.Base] = true
+			}
+		}
+		for _, c := range cs.Heroes {
+			if c != h && !bases[c.Base] {
+				try(h, c, func(t *Setup, c *Card) { t.Heroes[i] = c })
+			}
+		}
+	}
+	for _, c := range cs.Villains {
+		if c != s.Villain {
+			try(s.Villain, c, func(t *Setup, c *Card) { t.Villain = c })
+		}
+	}
+	for _, c := range cs.Environments {
+		if c != s.Environment {
+			try(s.Environment, c, func(t *Setup, c *Card) { t.Environment = c })
+		}
+	}
+	dist := func(w Swap) int {
+		d := w.Change - delta
+		if d < 0 {
+			d = -d
+		}
+		return d
+	}
+	sort.SliceStable(swaps, func(i, j int) bool { return dist(swaps[i]) < dist(swaps[j]) })
+	if len(swaps) > n {
+		swaps = swaps[:n]
+	}
+	return swaps
+}
+
+// allExpansions returns every expansion.
+func allExpansions() []ExpansionType {
+	exp := make([]ExpansionType, len(ExpansionNames))
+	for i := range exp {
+		exp[i] = ExpansionType(i)
+	}
+	return exp
+}
@@ -0,0 +1,95 @@
+package sentinels
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SynergyScorer rates how well a setup's heroes work together, apart from
+// the difficulty points, which stay the core score.  Opinions such as which
+// heroes combine well can be installed by assigning Synergies.
+type SynergyScorer interface {
+	// Synergy returns the team's synergy: above 0 if its heroes help each
+	// other, below 0 if they get in each other's way.
+	Synergy(s *Setup) Synergy
+}
+
+// Synergy is a team's synergy and the reasons for it.
+type Synergy struct {
+	Points int
+	Notes  []string `json:",omitempty"`
+}
+
+// Synergies, if set, rates each candidate team during generation.  Teams
+// scoring below Params.MinSynergy are passed over, and the setup found
+// carries its team's synergy.
+var Synergies SynergyScorer
+
+// SynergyRule is an entry in a SynergyMatrix: a team including all of
+// Heroes, with at least MinPlayers heroes if that is set, gets Points.  A
+// base hero's name also matches its variants.
+type SynergyRule struct {
+	Heroes     []string `json:"heroes"`
+	MinPlayers int      `json:"minPlayers,omitempty"`
+	Points     int      `json:"points"`
+	Note       string   `json:"note,omitempty"`
+}
+
+// SynergyMatrix is a SynergyScorer adding up the rules a team matches, as
+// maintained by players, e.g. that the Argent Adept loves big teams.
+type SynergyMatrix []SynergyRule
+
+// LoadSynergyMatrix reads a synergy matrix from a JSON list of rules,
+// checking that the heroes they name exist.
+func LoadSynergyMatrix(data []byte) (SynergyMatrix, error) {
+	if err := EnsureData(); err != nil {
+		return nil, err
+	}
+	var m SynergyMatrix
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	for i, r := range m {
+		if len(r.Heroes) == 0 {
+			return nil, fmt.Errorf("Synergy rule %d names no heroes.", i+1)
+		}
+		for _, n := range r.Heroes {
+			if c, ok := Lookup(n); !ok || c.Type != Hero {
+				return nil, fmt.Errorf("Synergy rule %d names unknown hero %q.", i+1, n)
+			}
+		}
+	}
+	return m, nil
+}
+
+// Synergy implements SynergyScorer.
+func (m SynergyMatrix) Synergy(s *Setup) Synergy {
+	var sy Synergy
+	for _, r := range m {
+		if len(s.Heroes) < r.MinPlayers || !r.matches(s.Heroes) {
+			continue
+		}
+		sy.Points += r.Points
+		if r.Note != "" {
+			sy.Notes = append(sy.Notes, r.Note)
+		}
+	}
+	return sy
+}
+
+// matches reports whether every hero the rule names is in the team.
+func (r SynergyRule) matches(team []*Card) bool {
+	for _, n := range r.Heroes {
+		found := false
+		for _, h := range team {
+			if sameCard(h.Name, n) || sameCard(h.Base, n) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package sentinels
+
+// splitAttempts is the number of different partitions FindForTwoTables
+// tries before giving up.
+const splitAttempts = 10
+
+// FindForTwoTables finds setups for two tables playing at once.  The cards
+// selected by p are split into two disjoint pools, keeping variants of a
+// hero or villain together since they share a physical deck.  The first
+// table gets pcA players at p's target; the second gets pcB players and a
+// difficulty within p's range (or tolerance) of the first's.
+func FindForTwoTables(p *Params, pcA, pcB int) (*Setup, *Setup, error) {
+	var err error
+	for i := 0; i < splitAttempts; i++ {
+		csA, csB := p.CardSet().split()
+		// exclude the other table's cards, so the seed records replay.
+		pa := *p
+		pa.Players = pcA
+		pa.Exclude = append(append([]string(nil), p.Exclude...), csB.names()...)
+		var a, b *Setup
+		if a, _, err = Find(&pa); err != nil {
+			continue
+		}
+		pb := *p
+		pb.Players = pcB
+		pb.Exclude = append(append([]string(nil), p.Exclude...), csA.names()...)
+		pb.ByTotal = true
+		pb.TargetTotal = a.Difficulty
+		if !p.ByTotal {
+			pb.Tolerance = p.Range
+		}
+		if b, _, err = Find(&pb); err != nil {
+			continue
+		}
+		return a, b, nil
+	}
+	return nil, nil, err
+}
+
+// names returns the names of all the cards in the set.
+func (cs *CardSet) names() []string {
+	var n []string
+	for _, l := range [][]*Card{cs.Heroes, cs.Villains, cs.Environments} {
+		for _, c := range l {
+			n = append(n, c.Name)
+		}
+	}
+	return n
+}
+
+// split randomly divides the card set into two halves, keeping cards with
+// the same base together.
+func (cs *CardSet) split() (*CardSet, *CardSet) {
+	a, b := new(CardSet), new(CardSet)
+	divide := func(cards []*Card, add func(*CardSet, *Card)) {
+		var decks []string
+		byDeck := make(map[string][]*Card)
+		for _, c := range cards {
+			if byDeck[c.Base] == nil {
+				decks = append(decks, c.Base)
+			}
+			byDeck[c.Base] = append(byDeck[c.Base], c)
+		}
+		for i, j := range randPerm(len(decks)) {
+			to := a
+			if i%2 == 1 {
+				to = b
+			}
+			for _, c := range byDeck[decks[j]] {
+				add(to, c)
+			}
+		}
+	}
+	divide(cs.Heroes, func(s *CardSet, c *Card) { s.Heroes = append(s.Heroes, c) })
+	divide(cs.Villains, func(s *CardSet, c *Card) { s.Villains = append(s.Villains, c) })
+	divide(cs.Environments, func(s *CardSet, c *Card) { s.Environments = append(s.Environments, c) })
+	return a, b
+}
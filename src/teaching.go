@@ -0,0 +1,48 @@
+package sentinels
+
+// Teaching games are set up for new players: the simplest heroes, a villain
+// tagged TeachingVillainTag, an environment tagged TeachingEnvTag and a
+// forgiving target.
+const (
+	TeachingVillainTag  = "simple" // villains with straightforward mechanics
+	TeachingEnvTag      = "calm"   // environments that rarely take over a game
+	TeachingLossPercent = 30       // the hardest target of a teaching game
+)
+
+// teaching returns a copy of the card set limited for a teaching game with
+// the given number of heroes.  Heroes are of the lowest complexity that
+// leaves a deck for each of them; villains and environments are limited to
+// those with the teaching tags, unless the set has none.
+func (cs *CardSet) teaching(players int) *CardSet {
+	complexity := 1
+	for ; complexity < 3; complexity++ {
+		decks := make(map[string]bool)
+		for _, c := range cs.Heroes {
+			if c.Complexity <= complexity {
+				decks[c.Base] = true
+			}
+		}
+		if len(decks) >= players {
+			break
+		}
+	}
+	tagged := func(cards []*Card, tag string) bool {
+		for _, c := range cards {
+			if c.HasTag(tag) {
+				return true
+			}
+		}
+		return false
+	}
+	simple := tagged(cs.Villains, TeachingVillainTag)
+	calm := tagged(cs.Environments, TeachingEnvTag)
+	return cs.filter(func(c *Card) bool {
+		switch c.Type {
+		case Hero:
+			return c.Complexity <= complexity
+		case Villain:
+			return !simple || c.HasTag(TeachingVillainTag)
+		}
+		return !calm || c.HasTag(TeachingEnvTag)
+	})
+}
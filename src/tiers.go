@@ -0,0 +1,89 @@
+package sentinels
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Tier is a villain's difficulty tier, derived from where its points fall
+// among all villains.
+type Tier int
+
+const (
+	AnyTier Tier = iota
+	Easy
+	Medium
+	Hard
+	Brutal
+)
+
+// TierNames are the names of the tiers, indexed by Tier.
+var TierNames = []string{"any", "easy", "medium", "hard", "brutal"}
+
+func (t Tier) String() string {
+	if t < 0 || int(t) >= len(TierNames) {
+		return fmt.Sprintf("Tier(%d)", int(t))
+	}
+	return strings.Title(TierNames[t])
+}
+
+// MarshalText encodes a tier by name, so presets stay readable.
+func (t Tier) MarshalText() ([]byte, error) {
+	if t < 0 || int(t) >= len(TierNames) {
+		return nil, fmt.Errorf("Unknown tier %d.", int(t))
+	}
+	return []byte(TierNames[t]), nil
+}
+
+func (t *Tier) UnmarshalText(b []byte) error {
+	v, err := ParseTier(string(b))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// ParseTier looks up a tier by name, ignoring case.  An empty name is AnyTier.
+func ParseTier(name string) (Tier, error) {
+	if name == "" {
+		return AnyTier, nil
+	}
+	for i, n := range TierNames {
+		if strings.EqualFold(n, name) {
+			return Tier(i), nil
+		}
+	}
+	return AnyTier, fmt.Errorf("Unknown tier %q.", name)
+}
+
+// villainScore returns a villain's points.  In advanced mode the advanced
+// score is weighted by its Confidence, the rest of the weight going to the
+// normal score, so that a score from a handful of games counts for little.
+func villainScore(c *Card, advanced bool) int {
+	if !advanced || !c.HasAdvancedData() {
+		return c.Points
+	}
+	return c.Points + int(math.Floor(c.Confidence()*float64(c.Advanced-c.Points)+0.5))
+}
+
+// VillainTier returns the tier of a villain: the quartile its points fall in
+// among all known villains, scored the same way.
+func VillainTier(c *Card, advanced bool) Tier {
+	var pts []int
+	for _, v := range loaded().cards {
+		if v.Type == Villain {
+			pts = append(pts, villainScore(v, advanced))
+		}
+	}
+	sort.Ints(pts)
+	below := sort.SearchInts(pts, villainScore(c, advanced))
+	return Tier(1 + below*4/len(pts))
+}
+
+// VillainTier returns the tier of the setup's villain.
+func (s *Setup) VillainTier() Tier {
+	return VillainTier(s.Villain, s.Advanced)
+}
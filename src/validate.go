@@ -0,0 +1,125 @@
+package sentinels
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MaxPoints bounds the difficulty points a card may sensibly have.
+const MaxPoints = 200
+
+// Diagnostic describes a problem in a card list.
+type Diagnostic struct {
+	Severity string // "error" or "warning"
+	Type     string // "hero", "villain" or "env"
+	Index    int    // position in the list of that type
+	Name     string
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s[%d] %q: %s", d.Severity, d.Type, d.Index, d.Name, d.Message)
+}
+
+// ValidateCards checks a card list in the same form as the embedded data's
+// "difficulty" field: names must be non-empty and unique, ignoring case and
+// punctuation, Base must name another card of the same type that is not
+// itself a variant, a hero's Nemesis must name a villain, a villain's Phases
+// must say when they begin, and points, including those by number of heroes
+// and phase, must be within MaxPoints of zero.  The list is usable if no
+// diagnostic has severity "error".
+func ValidateCards(dd *DifficultyData) []Diagnostic {
+	var ds []Diagnostic
+	seen := make(map[string]string) // names by CardKey
+	types := make(map[string]string)
+	villains := make(map[string]bool)
+	for _, d := range dd.Villain {
+		villains[d.Name] = true
+	}
+	lists := []struct {
+		typ   string
+		cards []Difficulty
+	}{{"hero", dd.Hero}, {"villain", dd.Villain}, {"env", dd.Env}}
+	for _, l := range lists {
+		names := make(map[string]Difficulty)
+		for _, d := range l.cards {
+			names[d.Name] = d
+		}
+		for i, d := range l.cards {
+			add := func(sev, format string, args ...interface{}) {
+				ds = append(ds, Diagnostic{sev, l.typ, i, d.Name, fmt.Sprintf(format, args...)})
+			}
+			if d.Name == "" {
+				add("error", "card has no name")
+			} else if prev, ok := seen[CardKey(d.Name)]; ok && prev == d.Name {
+				add("error", "name is already used by a %s", types[prev])
+			} else if ok {
+				add("error", "name differs only in case or punctuation from %q", prev)
+			} else {
+				seen[CardKey(d.Name)] = d.Name
+				types[d.Name] = l.typ
+			}
+			if d.Base != "" && d.Base != d.Name {
+				if b, ok := names[d.Base]; !ok {
+					add("error", "base %q is not a %s in the list", d.Base, l.typ)
+				} else if b.Base != "" && b.Base != b.Name {
+					add("error", "base %q is itself a variant of %q", d.Base, b.Base)
+				}
+			}
+			if d.Points < -MaxPoints || d.Points > MaxPoints {
+				add("error", "points %d are outside ±%d", d.Points, MaxPoints)
+			}
+			if l.typ != "hero" && d.Complexity != 0 {
+				add("warning", "only heroes have a complexity")
+			} else if d.Complexity < 0 || d.Complexity > 3 {
+				add("error", "complexity %d is outside 0-3", d.Complexity)
+			}
+			if l.typ != "hero" && len(d.ByPlayers) > 0 {
+				add("warning", "only heroes have points by number of heroes")
+			}
+			var counts []int
+			for n := range d.ByPlayers {
+				counts = append(counts, n)
+			}
+			sort.Ints(counts)
+			for _, n := range counts {
+				if n < 3 || n > len(dd.Nump)+2 {
+					add("error", "points are given for %d heroes; games have 3 to %d", n, len(dd.Nump)+2)
+				} else if p := d.Points + d.ByPlayers[n]; p < -MaxPoints || p > MaxPoints {
+					add("error", "points %d for %d heroes are outside ±%d", p, n, MaxPoints)
+				}
+			}
+			if l.typ != "hero" && d.Nemesis != "" {
+				add("warning", "only heroes have a nemesis")
+			} else if d.Nemesis != "" && !villains[d.Nemesis] {
+				add("error", "nemesis %q is not a villain in the list", d.Nemesis)
+			}
+			if l.typ != "villain" && len(d.Phases) > 0 {
+				add("warning", "only villains have phases")
+			}
+			for j, p := range d.Phases {
+				if p.When == "" {
+					add("error", "phase %d doesn't say when it begins", j)
+				}
+				if p.Points < -MaxPoints || p.Points > MaxPoints {
+					add("error", "phase %d points %d are outside ±%d", j, p.Points, MaxPoints)
+				}
+			}
+			if l.typ != "villain" && (d.Advanced != 0 || d.AdvCount != 0) {
+				add("warning", "only villains have advanced-mode data")
+			}
+			if l.typ == "villain" {
+				if d.AdvCount < 0 {
+					add("error", "advcount %d is negative", d.AdvCount)
+				}
+				if d.Advanced < -MaxPoints || d.Advanced > MaxPoints {
+					add("error", "advanced points %d are outside ±%d", d.Advanced, MaxPoints)
+				}
+				if d.AdvCount == 0 && d.Advanced != 0 {
+					add("warning", "advanced points are given without an advcount")
+				}
+			}
+		}
+	}
+	return ds
+}
@@ -0,0 +1,145 @@
+package sentinels
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultVetoes is the number of times a newly generated setup may be vetoed.
+const DefaultVetoes = 3
+
+// vetoTTL is how long an issued setup can be vetoed.
+const vetoTTL = 6 * time.Hour
+
+var (
+	vetoMu  sync.Mutex
+	pending = make(map[string]*issued)
+)
+
+// issued is a setup that can still be vetoed.
+type issued struct {
+	setup *Setup
+	time  time.Time
+}
+
+// issue runs the search and, if it succeeds, records the setup in the
+// history and makes it available to Veto.  The first run uses the seed
+// given in the parameters, if any.
+func (q *search) issue(vetoes int, vetoOf string) (*Setup, int, error) {
+	seed := q.params.Seed
+	if seed == 0 || vetoOf != "" {
+		seed = NewSeed()
+	}
+	s, i, err := q.run(seed)
+	if err != nil {
+		return nil, i, err
+	}
+	q.register(s, vetoes, vetoOf)
+	return s, i, nil
+}
+
+// register adds the setup's warnings, records it in the history and makes
+// it available to Veto.
+func (q *search) register(s *Setup, vetoes int, vetoOf string) {
+	s.warn()
+	q.enter(s, vetoes, vetoOf)
+}
+
+// enter gives the setup a token, records it in the history and makes it
+// available to Veto.
+func (q *search) enter(s *Setup, vetoes int, vetoOf string) {
+	s.Token = newToken()
+	s.VetoesLeft = vetoes
+	s.VetoOf = vetoOf
+	e := s.historyEntry()
+	e.Group = q.params.Group
+	DefaultHistory.Add(e)
+	c := *e
+	defer notify(Event{Generated, s, &c}) // once vetoMu is released
+
+	now := Now()
+	vetoMu.Lock()
+	defer vetoMu.Unlock()
+	for t, p := range pending {
+		if now.Sub(p.time) > vetoTTL {
+			delete(pending, t)
+		}
+	}
+	if vetoes > 0 {
+		pending[s.Token] = &issued{s, now}
+	}
+}
+
+// Veto rejects the setup identified by token and generates a replacement
+// using the same parameters.  Every setup vetoed along the chain is excluded
+// from the replacement, which can itself be vetoed one fewer time.
+func Veto(token string) (*Setup, int, error) {
+	vetoMu.Lock()
+	p, ok := pending[token]
+	delete(pending, token)
+	vetoMu.Unlock()
+	if !ok {
+		return nil, 0, errors.New("Unknown or expired setup; it can't be vetoed.")
+	}
+	old := p.setup
+	q := old.search
+	q.exclude[old.Key()] = true
+	s, i, err := q.issue(old.VetoesLeft-1, token)
+	if err != nil {
+		// leave the original setup in place.
+		delete(q.exclude, old.Key())
+		vetoMu.Lock()
+		pending[token] = p
+		vetoMu.Unlock()
+		return nil, i, err
+	}
+	DefaultHistory.MarkVetoed(token)
+	return s, i, nil
+}
+
+// Reinstate makes s current again in place of the setup that replaced it,
+// e.g. to undo a reroll.  The replacement is marked vetoed and s, no longer,
+// and s can be vetoed again if it has vetoes left.
+func Reinstate(s, replacement *Setup) {
+	DefaultHistory.MarkVetoed(replacement.Token)
+	DefaultHistory.ClearVetoed(s.Token)
+	vetoMu.Lock()
+	defer vetoMu.Unlock()
+	delete(pending, replacement.Token)
+	if s.VetoesLeft > 0 && s.search != nil {
+		delete(s.search.exclude, s.Key())
+		pending[s.Token] = &issued{s, Now()}
+	}
+}
+
+// Reissue records a copy of s, a setup Find returned earlier, as newly
+// generated: it has its own token and history entry and can be vetoed
+// DefaultVetoes times, without affecting s or the setups that replace it.
+// It lets a repeated search reuse a result for another player.
+func Reissue(s *Setup) *Setup {
+	c := *s
+	c.Heroes = append([]*Card(nil), s.Heroes...)
+	c.Seating = append([]Seat(nil), s.Seating...)
+	c.Warnings = append([]Warning(nil), s.Warnings...)
+	c.VetoOf, c.Notes, c.HouseRules = "", "", nil
+	vetoes := DefaultVetoes
+	q := &search{exclude: make(map[string]bool)}
+	if o := s.search; o != nil {
+		// only the fields a veto of s doesn't change are copied.
+		q = &search{data: o.data, cs: o.cs, pc: o.pc, lp: o.lp, min: o.min, max: o.max,
+			heroes: o.heroes, advanced: o.advanced, exclude: make(map[string]bool),
+			params: o.params, rngKind: o.rngKind, nemesis: o.nemesis}
+	} else {
+		vetoes = 0
+	}
+	c.search = q
+	q.enter(&c, vetoes, "")
+	return &c
+}
+
+// newToken returns a random token identifying a setup.
+func newToken() string {
+	return fmt.Sprintf("%016x", NewSeed())
+}
@@ -0,0 +1,113 @@
+package sentinels
+
+import "fmt"
+
+// Warning is advice about a generated setup, such as that its score rests
+// on little data, for UIs to show as a badge.
+type Warning struct {
+	Kind    string   // one of the Warn constants
+	Message string   // the warning in a sentence
+	Cards   []string `json:",omitempty"` // names of the cards it concerns
+}
+
+func (w Warning) String() string { return w.Message }
+
+// Kinds of warning.
+const (
+	WarnNoAdvancedData = "no-advanced-data" // the villain's normal difficulty stood in for advanced
+	WarnLowConfidence  = "low-confidence"   // the villain's advanced score is from few games
+	WarnApproximate    = "approximate"      // no setup in the target range was found
+	WarnNoNemesis      = "no-nemesis"       // a nemesis was preferred but none was found
+	WarnMatchup        = "matchup"          // the villain and environment conflict
+	WarnSpread         = "spread"           // the heroes' points are far apart
+	WarnUnplayed       = "unplayed"         // the villain has never been played
+)
+
+// SpreadWarning is the gap between the strongest and weakest heroes'
+// points above which a setup is warned to be lopsided.
+const SpreadWarning = 60
+
+// warningRules each return the warnings of one kind that apply to a setup.
+var warningRules = []func(*Setup) []Warning{
+	(*Setup).advancedWarnings,
+	(*Setup).conflictWarnings,
+	(*Setup).spreadWarnings,
+	(*Setup).unplayedWarnings,
+}
+
+// warn adds the warnings of every rule that applies to the setup.
+func (s *Setup) warn() {
+	for _, r := range warningRules {
+		s.Warnings = append(s.Warnings, r(s)...)
+	}
+}
+
+// advancedWarnings warns of missing or thin advanced-mode data.
+func (s *Setup) advancedWarnings() []Warning {
+	v := s.Villain
+	switch {
+	case !s.Advanced:
+	case !v.HasAdvancedData():
+		return []Warning{{WarnNoAdvancedData, fmt.Sprintf("There is no advanced-mode data for %s; its normal difficulty was used.", v.Name), []string{v.Name}}}
+	case v.AdvCount < ConfidentSamples:
+		return []Warning{{WarnLowConfidence, fmt.Sprintf("The advanced-mode score for %s is based on only %d games.", v.Name, v.AdvCount), []string{v.Name}}}
+	}
+	return nil
+}
+
+// conflictWarnings warns of each of the setup's conflicts.
+func (s *Setup) conflictWarnings() []Warning {
+	var r []Warning
+	for _, c := range s.Conflicts() {
+		r = append(r, Warning{WarnMatchup, fmt.Sprintf("%s conflicts with %s: %s", c.Villain, c.Environment, c.Reason), []string{s.Villain.Name, s.Environment.Name}})
+	}
+	return r
+}
+
+// spreadWarnings warns when the heroes' points are more than SpreadWarning
+// apart, so that one player carries the team or is carried by it.
+func (s *Setup) spreadWarnings() []Warning {
+	if len(s.Heroes) < 2 {
+		return nil
+	}
+	lo, hi := s.Heroes[0], s.Heroes[0]
+	for _, h := range s.Heroes[1:] {
+		if s.HeroPoints(h) < s.HeroPoints(lo) {
+			lo = h
+		}
+		if s.HeroPoints(h) > s.HeroPoints(hi) {
+			hi = h
+		}
+	}
+	if s.HeroPoints(hi)-s.HeroPoints(lo) <= SpreadWarning {
+		return nil
+	}
+	return []Warning{{WarnSpread, fmt.Sprintf("%s (%+d) is much stronger than %s (%+d).", lo.Name, s.HeroPoints(lo), hi.Name, s.HeroPoints(hi)), []string{lo.Name, hi.Name}}}
+}
+
+// unplayedWarnings warns when no result has been recorded against the
+// villain, or any of its variants, in a history that has results.
+func (s *Setup) unplayedWarnings() []Warning {
+	played, any := DefaultHistory.played(s.Villain.Base)
+	if !any || played {
+		return nil
+	}
+	return []Warning{{WarnUnplayed, fmt.Sprintf("%s has never been played before.", s.Villain.Name), []string{s.Villain.Name}}}
+}
+
+// played reports whether the history records a result against the base
+// villain or one of its variants, and whether it records any results.
+func (h *History) played(base string) (played, any bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range h.Entries {
+		if e.Result == "" {
+			continue
+		}
+		any = true
+		if c, ok := Lookup(e.Villain); ok && c.Base == base {
+			return true, true
+		}
+	}
+	return false, any
+}
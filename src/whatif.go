@@ -0,0 +1,124 @@
+package sentinels
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// PoolStats describes what a card set can produce for a number of heroes.
+type PoolStats struct {
+	Heroes, Villains, Environments int
+	// Setups is the number of different setups, counting heroes that share
+	// a deck as alternatives.
+	Setups                       int64
+	MinDifficulty, MaxDifficulty int
+	MinLoss, MaxLoss             int // expected loss percentages at the extremes
+}
+
+// PoolStats counts the setups with n heroes the card set can make and the
+// range of difficulty they span.
+func (cs *CardSet) PoolStats(n int) PoolStats {
+	st := PoolStats{Heroes: len(cs.Heroes), Villains: len(cs.Villains), Environments: len(cs.Environments)}
+	if EnsureData() != nil {
+		return st
+	}
+	sd := loaded().sd
+	if n < 3 || n > len(sd.Difficulty.Nump)+2 {
+		return st
+	}
+	// one hero may be picked from each deck.
+	type deck struct{ size, min, max int }
+	decks := make(map[string]*deck)
+	for _, h := range cs.Heroes {
+		p := h.PointsFor(n)
+		d := decks[h.Base]
+		if d == nil {
+			decks[h.Base] = &deck{1, p, p}
+			continue
+		}
+		d.size++
+		if p < d.min {
+			d.min = p
+		}
+		if p > d.max {
+			d.max = p
+		}
+	}
+	if len(decks) < n || len(cs.Villains) == 0 || len(cs.Environments) == 0 {
+		return st
+	}
+	// ways[k] is the number of ways to pick k heroes from different decks.
+	ways := make([]int64, n+1)
+	ways[0] = 1
+	var mins, maxs []int
+	for _, d := range decks {
+		for k := n; k > 0; k-- {
+			ways[k] += ways[k-1] * int64(d.size)
+		}
+		mins, maxs = append(mins, d.min), append(maxs, d.max)
+	}
+	st.Setups = ways[n] * int64(len(cs.Villains)) * int64(len(cs.Environments))
+	sort.Ints(mins)
+	sort.Sort(sort.Reverse(sort.IntSlice(maxs)))
+	pc := sd.Difficulty.Nump[n-3].Points
+	st.MinDifficulty, st.MaxDifficulty = pc, pc
+	for i := 0; i < n; i++ {
+		st.MinDifficulty += mins[i]
+		st.MaxDifficulty += maxs[i]
+	}
+	st.MinDifficulty += minPoints(cs.Villains) + minPoints(cs.Environments)
+	st.MaxDifficulty += maxPoints(cs.Villains) + maxPoints(cs.Environments)
+	st.MinLoss, st.MaxLoss = sd.LossPercentFor(st.MinDifficulty, n), sd.LossPercentFor(st.MaxDifficulty, n)
+	return st
+}
+
+func minPoints(cards []*Card) int {
+	m := cards[0].Points
+	for _, c := range cards[1:] {
+		if c.Points < m {
+			m = c.Points
+		}
+	}
+	return m
+}
+
+func maxPoints(cards []*Card) int {
+	m := cards[0].Points
+	for _, c := range cards[1:] {
+		if c.Points > m {
+			m = c.Points
+		}
+	}
+	return m
+}
+
+// Purchase compares a collection with and without one more expansion.
+type Purchase struct {
+	Expansion     ExpansionType
+	Players       int
+	Before, After PoolStats
+	NewCards      []*Card // cards the expansion adds
+}
+
+// WhatIf reports how buying the expansion buy would change the setups with
+// the given number of heroes that the owned expansions can make.
+func WhatIf(owned []ExpansionType, buy ExpansionType, players int) (*Purchase, error) {
+	if err := EnsureData(); err != nil {
+		return nil, err
+	}
+	if n := len(loaded().sd.Difficulty.Nump) + 2; players < 3 || players > n {
+		return nil, fmt.Errorf("A setup needs 3 to %d heroes, not %d.", n, players)
+	}
+	for _, e := range owned {
+		if e == buy {
+			return nil, errors.New("That expansion is already owned.")
+		}
+	}
+	before := Select(WithExpansions(owned...))
+	after := Select(WithExpansions(append(append([]ExpansionType(nil), owned...), buy)...))
+	pu := &Purchase{Expansion: buy, Players: players, Before: before.PoolStats(players), After: after.PoolStats(players)}
+	added := Select(WithExpansions(buy))
+	pu.NewCards = append(append(append(pu.NewCards, added.Heroes...), added.Villains...), added.Environments...)
+	return pu, nil
+}